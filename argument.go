@@ -1,6 +1,14 @@
 package sqlmock
 
-import "database/sql/driver"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
 
 // Argument interface allows to match
 // any argument in specific way when used with
@@ -9,6 +17,23 @@ type Argument interface {
 	Match(driver.Value) bool
 }
 
+// ArgumentDescriber is an Argument, which describes the
+// arguments it matches. The description is used in place
+// of the matcher type, when the expectation is printed or
+// an argument does not match.
+type ArgumentDescriber interface {
+	Argument
+	String() string
+}
+
+// ArgumentWithError is an Argument, which explains why an
+// argument was rejected. MatchErr returns nil if the argument
+// matches and must agree with Match.
+type ArgumentWithError interface {
+	Argument
+	MatchErr(driver.Value) error
+}
+
 // AnyArg will return an Argument which can
 // match any kind of arguments.
 //
@@ -22,3 +47,425 @@ type anyArgument struct{}
 func (a anyArgument) Match(_ driver.Value) bool {
 	return true
 }
+
+func (a anyArgument) String() string {
+	return "any argument"
+}
+
+// ArgumentFunc type is an adapter to allow the use of
+// ordinary functions as argument matchers.
+type ArgumentFunc func(driver.Value) bool
+
+// Match implements the Argument interface
+func (f ArgumentFunc) Match(v driver.Value) bool {
+	return f(v)
+}
+
+// describedArgument is an argument matcher, which
+// describes itself and explains a mismatch
+type describedArgument struct {
+	desc  string
+	match func(driver.Value) error
+}
+
+func (a describedArgument) Match(v driver.Value) bool {
+	return a.match(v) == nil
+}
+
+func (a describedArgument) MatchErr(v driver.Value) error {
+	return a.match(v)
+}
+
+func (a describedArgument) String() string {
+	return a.desc
+}
+
+// The argument matchers below receive the actual arguments converted
+// to driver values, which are int64, float64, bool, []byte, string,
+// time.Time or nil. The expected values given to them are converted
+// the same way with driver.DefaultParameterConverter, so that
+// ArgEq(1) matches an int64 argument.
+
+// ArgEq will return an Argument, which matches an argument
+// equal to the given value.
+func ArgEq(expected interface{}) Argument {
+	want := mustConvertArg("ArgEq", expected)
+	return describedArgument{
+		desc: fmt.Sprintf("equal to [%T - %+v]", want, want),
+		match: func(v driver.Value) error {
+			if !reflect.DeepEqual(want, v) {
+				return fmt.Errorf("the values differ")
+			}
+			return nil
+		},
+	}
+}
+
+// ArgRegexp will return an Argument, which matches a string
+// or []byte argument against the regular expression.
+// It panics if the expression cannot be compiled.
+func ArgRegexp(expr string) Argument {
+	re := regexp.MustCompile(expr)
+	return describedArgument{
+		desc: fmt.Sprintf("matching regexp %q", expr),
+		match: func(v driver.Value) error {
+			s, ok := argString(v)
+			switch {
+			case !ok:
+				return fmt.Errorf("%T is not a string", v)
+			case !re.MatchString(s):
+				return fmt.Errorf("%q does not match", s)
+			}
+			return nil
+		},
+	}
+}
+
+// ArgOfType will return an Argument, which matches an argument
+// of the same driver value type as the example, for example
+// ArgOfType(time.Time{}) or ArgOfType(0) matching any int64.
+func ArgOfType(example interface{}) Argument {
+	typ := reflect.TypeOf(mustConvertArg("ArgOfType", example))
+	return describedArgument{
+		desc: fmt.Sprintf("of type %s", typ),
+		match: func(v driver.Value) error {
+			if v == nil || reflect.TypeOf(v) != typ {
+				return fmt.Errorf("got type %T", v)
+			}
+			return nil
+		},
+	}
+}
+
+// ArgBetween will return an Argument, which matches a number,
+// a time.Time or a string argument within the given range,
+// both bounds included.
+func ArgBetween(min, max interface{}) Argument {
+	lo, hi := mustConvertArg("ArgBetween", min), mustConvertArg("ArgBetween", max)
+	return describedArgument{
+		desc: fmt.Sprintf("between %+v and %+v", lo, hi),
+		match: func(v driver.Value) error {
+			cmpLo, ok := compareArgs(v, lo)
+			if !ok {
+				return fmt.Errorf("%T cannot be compared with %T", v, lo)
+			}
+			if cmpLo < 0 {
+				return fmt.Errorf("it is less than %+v", lo)
+			}
+			cmpHi, ok := compareArgs(v, hi)
+			if !ok {
+				return fmt.Errorf("%T cannot be compared with %T", v, hi)
+			}
+			if cmpHi > 0 {
+				return fmt.Errorf("it is greater than %+v", hi)
+			}
+			return nil
+		},
+	}
+}
+
+// ArgContains will return an Argument, which matches a string
+// or []byte argument containing the given substring.
+func ArgContains(substr string) Argument {
+	return describedArgument{
+		desc: fmt.Sprintf("containing %q", substr),
+		match: func(v driver.Value) error {
+			s, ok := argString(v)
+			switch {
+			case !ok:
+				return fmt.Errorf("%T is not a string", v)
+			case !strings.Contains(s, substr):
+				return fmt.Errorf("%q does not contain it", s)
+			}
+			return nil
+		},
+	}
+}
+
+// ArgJSONEq will return an Argument, which matches a string or []byte
+// argument holding a JSON document equal to the given one, regardless of
+// the formatting and the order of object keys.
+// It panics if the given document is not valid JSON.
+func ArgJSONEq(expected string) Argument {
+	var want interface{}
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		panic(fmt.Sprintf("ArgJSONEq: invalid JSON document %q: %s", expected, err))
+	}
+	return describedArgument{
+		desc: fmt.Sprintf("JSON equal to %s", expected),
+		match: func(v driver.Value) error {
+			s, ok := argString(v)
+			if !ok {
+				return fmt.Errorf("%T is not a string", v)
+			}
+			var got interface{}
+			if err := json.Unmarshal([]byte(s), &got); err != nil {
+				return fmt.Errorf("invalid JSON document: %s", err)
+			}
+			if !reflect.DeepEqual(want, got) {
+				return fmt.Errorf("the documents differ")
+			}
+			return nil
+		},
+	}
+}
+
+// ArgTimeWithin will return an Argument, which matches a time.Time
+// argument no further than tolerance from the given time.
+func ArgTimeWithin(t time.Time, tolerance time.Duration) Argument {
+	return describedArgument{
+		desc: fmt.Sprintf("within %s of %s", tolerance, t.Format(time.RFC3339Nano)),
+		match: func(v driver.Value) error {
+			actual, ok := v.(time.Time)
+			if !ok {
+				return fmt.Errorf("%T is not a time.Time", v)
+			}
+			if diff := actual.Sub(t); diff < -tolerance || diff > tolerance {
+				return fmt.Errorf("it is %s off", diff)
+			}
+			return nil
+		},
+	}
+}
+
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ArgUUID will return an Argument, which matches a UUID, either in its
+// canonical string form or as 16 raw bytes.
+func ArgUUID() Argument {
+	return describedArgument{
+		desc: "a UUID",
+		match: func(v driver.Value) error {
+			switch uuid := v.(type) {
+			case string:
+				if !uuidRe.MatchString(uuid) {
+					return fmt.Errorf("%q is not in the canonical form", uuid)
+				}
+				return nil
+			case []byte:
+				if len(uuid) != 16 && !uuidRe.Match(uuid) {
+					return fmt.Errorf("%d bytes are neither raw nor in the canonical form", len(uuid))
+				}
+				return nil
+			}
+			return fmt.Errorf("%T is neither a string nor []byte", v)
+		},
+	}
+}
+
+// ArgNotNil will return an Argument, which matches any argument but NULL.
+func ArgNotNil() Argument {
+	return describedArgument{
+		desc: "not NULL",
+		match: func(v driver.Value) error {
+			if v == nil {
+				return fmt.Errorf("it is NULL")
+			}
+			return nil
+		},
+	}
+}
+
+// ArgNil will return an Argument, which matches a NULL argument.
+func ArgNil() Argument {
+	return describedArgument{
+		desc: "NULL",
+		match: func(v driver.Value) error {
+			if v != nil {
+				return fmt.Errorf("it is not NULL")
+			}
+			return nil
+		},
+	}
+}
+
+// ArgAnd will return an Argument, which matches an argument
+// matched by all the given arguments.
+func ArgAnd(args ...Argument) Argument {
+	return describedArgument{
+		desc: "all of (" + argNames(args) + ")",
+		match: func(v driver.Value) error {
+			for _, arg := range args {
+				if err := argMatchErr(arg, v); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// ArgOr will return an Argument, which matches an argument
+// matched by any of the given arguments.
+func ArgOr(args ...Argument) Argument {
+	return describedArgument{
+		desc: "any of (" + argNames(args) + ")",
+		match: func(v driver.Value) error {
+			var reasons []string
+			for _, arg := range args {
+				err := argMatchErr(arg, v)
+				if err == nil {
+					return nil
+				}
+				reasons = append(reasons, err.Error())
+			}
+			return fmt.Errorf("%s", strings.Join(reasons, "; "))
+		},
+	}
+}
+
+// ArgNot will return an Argument, which matches an argument
+// not matched by the given argument.
+func ArgNot(arg Argument) Argument {
+	return describedArgument{
+		desc: "not (" + argName(arg) + ")",
+		match: func(v driver.Value) error {
+			if arg.Match(v) {
+				return fmt.Errorf("it is %s", argName(arg))
+			}
+			return nil
+		},
+	}
+}
+
+// argName returns the description of an argument matcher,
+// or its type if it does not describe itself
+func argName(arg Argument) string {
+	if d, ok := arg.(ArgumentDescriber); ok {
+		return d.String()
+	}
+	return fmt.Sprintf("%T", arg)
+}
+
+func argNames(args []Argument) string {
+	names := make([]string, len(args))
+	for i, arg := range args {
+		names[i] = argName(arg)
+	}
+	return strings.Join(names, ", ")
+}
+
+// argMatchErr matches the value, explaining the mismatch
+// if the matcher is able to
+func argMatchErr(arg Argument, v driver.Value) error {
+	if m, ok := arg.(ArgumentWithError); ok {
+		return m.MatchErr(v)
+	}
+	if !arg.Match(v) {
+		return fmt.Errorf("it is not %s", argName(arg))
+	}
+	return nil
+}
+
+// argMatches matches the argument at pos, its index or quoted name,
+// with the custom matcher, the error describes what the matcher
+// expected and why it rejected the argument, if the matcher
+// implements ArgumentDescriber or ArgumentWithError
+func argMatches(pos string, matcher Argument, arg interface{}, v driver.Value) error {
+	var reason error
+	if m, ok := matcher.(ArgumentWithError); ok {
+		if reason = m.MatchErr(v); reason == nil {
+			return nil
+		}
+	} else if matcher.Match(v) {
+		return nil
+	}
+
+	d, described := matcher.(ArgumentDescriber)
+	switch {
+	case described && reason != nil:
+		return fmt.Errorf("argument %s expected %s does not match actual [%T - %+v]: %s", pos, d, v, v, reason)
+	case described:
+		return fmt.Errorf("argument %s expected %s does not match actual [%T - %+v]", pos, d, v, v)
+	case reason != nil:
+		return fmt.Errorf("matcher %T could not match %s argument %T - %+v: %s", matcher, pos, arg, arg, reason)
+	}
+	return fmt.Errorf("matcher %T could not match %s argument %T - %+v", matcher, pos, arg, arg)
+}
+
+// describeArg returns the representation of an expected argument
+// used when printing the expectation
+func describeArg(arg driver.Value) string {
+	if d, ok := arg.(ArgumentDescriber); ok {
+		return d.String()
+	}
+	return fmt.Sprintf("%+v", arg)
+}
+
+// mustConvertArg converts a value given to an argument matcher
+// to a driver value, it panics if the value cannot be converted
+func mustConvertArg(matcher string, v interface{}) driver.Value {
+	dv, err := driver.DefaultParameterConverter.ConvertValue(v)
+	if err != nil {
+		panic(fmt.Sprintf("%s: could not convert %T - %+v to driver value: %s", matcher, v, v, err))
+	}
+	return dv
+}
+
+// argString returns the text of string and []byte arguments
+func argString(v driver.Value) (string, bool) {
+	switch s := v.(type) {
+	case string:
+		return s, true
+	case []byte:
+		return string(s), true
+	}
+	return "", false
+}
+
+// compareArgs compares two numbers, times or strings,
+// it reports false if they cannot be compared
+func compareArgs(a, b driver.Value) (int, bool) {
+	if x, ok := a.(int64); ok {
+		if y, ok := b.(int64); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	if x, ok := argFloat(a); ok {
+		y, ok := argFloat(b)
+		switch {
+		case !ok:
+			return 0, false
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	}
+	if x, ok := a.(time.Time); ok {
+		y, ok := b.(time.Time)
+		switch {
+		case !ok:
+			return 0, false
+		case x.Before(y):
+			return -1, true
+		case x.After(y):
+			return 1, true
+		}
+		return 0, true
+	}
+	if x, ok := argString(a); ok {
+		if y, ok := argString(b); ok {
+			return strings.Compare(x, y), true
+		}
+	}
+	return 0, false
+}
+
+// argFloat returns the value of numeric arguments
+func argFloat(v driver.Value) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -2,6 +2,9 @@ package sqlmock
 
 import (
 	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 )
@@ -56,3 +59,333 @@ func TestByteSliceArgument(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 }
+
+func ExampleArgRegexp() {
+	db, mock, _ := New()
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO users").
+		WithArgs(ArgRegexp(`^[a-z]+@example\.com$`), ArgUUID()).
+		WillReturnResult(NewResult(1, 1))
+
+	_, err := db.Exec("INSERT INTO users(email, token) VALUES (?, ?)", "john@example.com", "6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	fmt.Println(err)
+	// Output: <nil>
+}
+
+func ExampleArgBetween() {
+	db, mock, _ := New()
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT name FROM users").
+		WithArgs(ArgBetween(18, 65)).
+		WillReturnRows(NewRows([]string{"name"}).AddRow("john"))
+
+	_, err := db.Query("SELECT name FROM users WHERE age = ?", 99)
+	fmt.Println(err)
+	// Output: Query 'SELECT name FROM users WHERE age = ?', arguments do not match: argument 0 expected between 18 and 65 does not match actual [int64 - 99]: it is greater than 65
+}
+
+func ExampleArgumentFunc() {
+	db, mock, _ := New()
+	defer db.Close()
+
+	even := ArgumentFunc(func(v driver.Value) bool {
+		n, ok := v.(int64)
+		return ok && n%2 == 0
+	})
+	mock.ExpectExec("DELETE FROM users").WithArgs(even).WillReturnResult(NewResult(0, 1))
+
+	_, err := db.Exec("DELETE FROM users WHERE id = ?", 4)
+	fmt.Println(err)
+	// Output: <nil>
+}
+
+func ExampleArgEq() {
+	db, mock, _ := New()
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE users").WithArgs(ArgEq(1)).WillReturnResult(NewResult(0, 1))
+
+	_, err := db.Exec("UPDATE users SET active = true WHERE id = ?", 2)
+	fmt.Println(err)
+	// Output: ExecQuery 'UPDATE users SET active = true WHERE id = ?', arguments do not match: argument 0 expected equal to [int64 - 1] does not match actual [int64 - 2]: the values differ
+}
+
+func ExampleArgOfType() {
+	db, mock, _ := New()
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO users").
+		WithArgs(ArgOfType(""), ArgOfType(time.Time{})).
+		WillReturnResult(NewResult(1, 1))
+
+	_, err := db.Exec("INSERT INTO users(name, created_at) VALUES (?, ?)", "john", time.Now())
+	fmt.Println(err)
+	// Output: <nil>
+}
+
+func ExampleArgContains() {
+	db, mock, _ := New()
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id FROM users").
+		WithArgs(ArgContains("@example.com")).
+		WillReturnRows(NewRows([]string{"id"}).AddRow(1))
+
+	_, err := db.Query("SELECT id FROM users WHERE email = ?", "john@example.org")
+	fmt.Println(err)
+	// Output: Query 'SELECT id FROM users WHERE email = ?', arguments do not match: argument 0 expected containing "@example.com" does not match actual [string - john@example.org]: "john@example.org" does not contain it
+}
+
+func ExampleArgJSONEq() {
+	db, mock, _ := New()
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE users").
+		WithArgs(ArgJSONEq(`{"theme": "dark", "lang": "en"}`)).
+		WillReturnResult(NewResult(0, 1))
+
+	_, err := db.Exec("UPDATE users SET settings = ?", `{"lang":"en","theme":"light"}`)
+	fmt.Println(err)
+	// Output: ExecQuery 'UPDATE users SET settings = ?', arguments do not match: argument 0 expected JSON equal to {"theme": "dark", "lang": "en"} does not match actual [string - {"lang":"en","theme":"light"}]: the documents differ
+}
+
+func ExampleArgTimeWithin() {
+	db, mock, _ := New()
+	defer db.Close()
+
+	now := time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC)
+	mock.ExpectExec("UPDATE users").
+		WithArgs(ArgTimeWithin(now, time.Second)).
+		WillReturnResult(NewResult(0, 1))
+
+	_, err := db.Exec("UPDATE users SET seen_at = ?", now.Add(time.Minute))
+	fmt.Println(err)
+	// Output: ExecQuery 'UPDATE users SET seen_at = ?', arguments do not match: argument 0 expected within 1s of 2020-03-04T05:06:07Z does not match actual [time.Time - 2020-03-04 05:07:07 +0000 UTC]: it is 1m0s off
+}
+
+func ExampleArgUUID() {
+	db, mock, _ := New()
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO sessions").WithArgs(ArgUUID()).WillReturnResult(NewResult(1, 1))
+
+	_, err := db.Exec("INSERT INTO sessions(token) VALUES (?)", "not-a-uuid")
+	fmt.Println(err)
+	// Output: ExecQuery 'INSERT INTO sessions(token) VALUES (?)', arguments do not match: argument 0 expected a UUID does not match actual [string - not-a-uuid]: "not-a-uuid" is not in the canonical form
+}
+
+func ExampleArgNil() {
+	db, mock, _ := New()
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE users").
+		WithArgs(ArgNil(), ArgNotNil()).
+		WillReturnResult(NewResult(0, 1))
+
+	_, err := db.Exec("UPDATE users SET deleted_at = ? WHERE id = ?", nil, 1)
+	fmt.Println(err)
+	// Output: <nil>
+}
+
+func ExampleArgOr() {
+	db, mock, _ := New()
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE users").
+		WithArgs(ArgOr(ArgEq("admin"), ArgEq("editor"))).
+		WillReturnResult(NewResult(0, 1))
+
+	_, err := db.Exec("UPDATE users SET role = ?", "guest")
+	fmt.Println(err)
+	// Output: ExecQuery 'UPDATE users SET role = ?', arguments do not match: argument 0 expected any of (equal to [string - admin], equal to [string - editor]) does not match actual [string - guest]: the values differ; the values differ
+}
+
+func ExampleArgNot() {
+	db, mock, _ := New()
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE users").
+		WithArgs(ArgNot(ArgRegexp(`^\s*$`))).
+		WillReturnResult(NewResult(0, 1))
+
+	_, err := db.Exec("UPDATE users SET name = ?", "  ")
+	fmt.Println(err)
+	// Output: ExecQuery 'UPDATE users SET name = ?', arguments do not match: argument 0 expected not (matching regexp "^\\s*$") does not match actual [string -   ]: it is matching regexp "^\\s*$"
+}
+
+func ExampleArgAnd() {
+	db, mock, _ := New()
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE users").
+		WithArgs(ArgAnd(ArgNotNil(), ArgNot(ArgContains("admin"))), ArgJSONEq(`{"theme": "dark", "lang": "en"}`)).
+		WillReturnResult(NewResult(0, 1))
+
+	_, err := db.Exec("UPDATE users SET name = ?, settings = ?", "john", `{"lang":"en","theme":"dark"}`)
+	fmt.Println(err)
+	// Output: <nil>
+}
+
+func TestArgumentMatchers(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name  string
+		arg   Argument
+		value driver.Value
+		match bool
+	}{
+		{"eq int", ArgEq(5), int64(5), true},
+		{"eq other int", ArgEq(5), int64(6), false},
+		{"eq string", ArgEq("john"), "john", true},
+		{"eq nil", ArgEq(nil), nil, true},
+		{"regexp string", ArgRegexp("^jo"), "john", true},
+		{"regexp bytes", ArgRegexp("^jo"), []byte("john"), true},
+		{"regexp no match", ArgRegexp("^jo"), "jane", false},
+		{"regexp number", ArgRegexp("5"), int64(5), false},
+		{"of type int", ArgOfType(0), int64(7), true},
+		{"of type time", ArgOfType(time.Time{}), now, true},
+		{"of type mismatch", ArgOfType(""), int64(7), false},
+		{"of type nil", ArgOfType(""), nil, false},
+		{"between ints", ArgBetween(1, 10), int64(10), true},
+		{"between lower", ArgBetween(1, 10), int64(0), false},
+		{"between floats", ArgBetween(0.5, 1.5), int64(1), true},
+		{"between times", ArgBetween(now.Add(-time.Hour), now), now.Add(-time.Minute), true},
+		{"between times after", ArgBetween(now.Add(-time.Hour), now), now.Add(time.Minute), false},
+		{"between strings", ArgBetween("a", "c"), "b", true},
+		{"between mixed", ArgBetween(1, 10), "5", false},
+		{"contains", ArgContains("oh"), "john", true},
+		{"contains bytes", ArgContains("oh"), []byte("john"), true},
+		{"contains no match", ArgContains("oh"), "jane", false},
+		{"json eq", ArgJSONEq(`{"a": [1, 2], "b": null}`), `{"b":null,"a":[1,2]}`, true},
+		{"json different", ArgJSONEq(`{"a": 1}`), `{"a": 2}`, false},
+		{"json invalid", ArgJSONEq(`{"a": 1}`), `{"a": `, false},
+		{"time within", ArgTimeWithin(now, time.Second), now.Add(-500 * time.Millisecond), true},
+		{"time outside", ArgTimeWithin(now, time.Second), now.Add(2 * time.Second), false},
+		{"time not a time", ArgTimeWithin(now, time.Second), "now", false},
+		{"uuid", ArgUUID(), "6BA7B810-9DAD-11D1-80B4-00C04FD430C8", true},
+		{"uuid bytes", ArgUUID(), make([]byte, 16), true},
+		{"uuid invalid", ArgUUID(), "6ba7b810-9dad-11d1-80b4", false},
+		{"not nil", ArgNotNil(), int64(0), true},
+		{"not nil on nil", ArgNotNil(), nil, false},
+		{"nil", ArgNil(), nil, true},
+		{"nil on value", ArgNil(), "", false},
+		{"and", ArgAnd(ArgOfType(""), ArgContains("o")), "john", true},
+		{"and no match", ArgAnd(ArgOfType(""), ArgContains("x")), "john", false},
+		{"or", ArgOr(ArgNil(), ArgEq(1)), int64(1), true},
+		{"or no match", ArgOr(ArgNil(), ArgEq(1)), int64(2), false},
+		{"not", ArgNot(ArgNil()), int64(1), true},
+	}
+
+	for _, c := range cases {
+		if actual := c.arg.Match(c.value); actual != c.match {
+			t.Errorf("%s: expected match to be %t for %T - %+v, but got %t", c.name, c.match, c.value, c.value, actual)
+		}
+	}
+}
+
+func TestArgumentMatcherInvalidExpectation(t *testing.T) {
+	for name, build := range map[string]func(){
+		"regexp": func() { ArgRegexp("(") },
+		"json":   func() { ArgJSONEq("{") },
+		"eq":     func() { ArgEq(struct{}{}) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic on invalid expectation", name)
+				}
+			}()
+			build()
+		}()
+	}
+}
+
+// evenArgument describes itself and explains a mismatch
+type evenArgument struct{}
+
+func (evenArgument) Match(v driver.Value) bool {
+	return evenArgument{}.MatchErr(v) == nil
+}
+
+func (evenArgument) MatchErr(v driver.Value) error {
+	n, ok := v.(int64)
+	if !ok {
+		return errors.New("not an integer")
+	}
+	if n%2 != 0 {
+		return fmt.Errorf("%d is odd", n)
+	}
+	return nil
+}
+
+func (evenArgument) String() string {
+	return "an even number"
+}
+
+// positiveArgument only explains a mismatch
+type positiveArgument struct{}
+
+func (positiveArgument) Match(v driver.Value) bool {
+	return positiveArgument{}.MatchErr(v) == nil
+}
+
+func (positiveArgument) MatchErr(v driver.Value) error {
+	if n, ok := v.(int64); !ok || n <= 0 {
+		return errors.New("not a positive integer")
+	}
+	return nil
+}
+
+func TestDescriptiveArgumentMismatch(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		arg      driver.Value
+		value    int
+		expected string
+	}{
+		{evenArgument{}, 3, "argument 0 expected an even number does not match actual [int64 - 3]: 3 is odd"},
+		{positiveArgument{}, -3, "matcher sqlmock.positiveArgument could not match 0 argument driver.NamedValue - {Name: Ordinal:1 Value:-3}: not a positive integer"},
+		{ArgNot(ArgOr(ArgNil(), ArgBetween(-5, 5))), 3, "argument 0 expected not (any of (NULL, between -5 and 5)) does not match actual [int64 - 3]: it is any of (NULL, between -5 and 5)"},
+		{ArgAnd(ArgNotNil(), AnyTime{}), 3, "argument 0 expected all of (not NULL, sqlmock.AnyTime) does not match actual [int64 - 3]: it is not sqlmock.AnyTime"},
+	}
+
+	for _, c := range cases {
+		db, mock, err := New()
+		if err != nil {
+			t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		mock.ExpectExec("UPDATE users").WithArgs(c.arg).WillReturnResult(NewResult(0, 1))
+
+		_, err = db.Exec("UPDATE users SET score = ?", c.value)
+		if err == nil {
+			t.Errorf("expected an error for %s", describeArg(c.arg))
+		} else if !strings.HasSuffix(err.Error(), c.expected) {
+			t.Errorf("expected error to end with:\n%s\nbut got:\n%s", c.expected, err)
+		}
+		db.Close()
+	}
+}
+
+func TestExpectationDescribesArguments(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	query := mock.ExpectQuery("SELECT").WithArgs(evenArgument{}, ArgRegexp("^jo"), AnyArg(), 5)
+	exec := mock.ExpectExec("UPDATE").WithArgs(ArgTimeWithin(time.Unix(0, 0).UTC(), time.Second))
+
+	for _, expected := range []string{"0 - an even number", `1 - matching regexp "^jo"`, "2 - any argument", "3 - 5"} {
+		if !strings.Contains(query.String(), expected) {
+			t.Errorf("expected query expectation to contain %q, but got:\n%s", expected, query)
+		}
+	}
+	if expected := "0 - within 1s of 1970-01-01T00:00:00Z"; !strings.Contains(exec.String(), expected) {
+		t.Errorf("expected exec expectation to contain %q, but got:\n%s", expected, exec)
+	}
+}
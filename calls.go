@@ -0,0 +1,18 @@
+package sqlmock
+
+// CallKind names the driver method which received a call.
+type CallKind string
+
+// Kinds of calls recorded in the call history.
+const (
+	CallQuery        CallKind = "Query"
+	CallExec         CallKind = "Exec"
+	CallPrepare      CallKind = "Prepare"
+	CallBegin        CallKind = "Begin"
+	CallCommit       CallKind = "Commit"
+	CallRollback     CallKind = "Rollback"
+	CallPing         CallKind = "Ping"
+	CallClose        CallKind = "Close"
+	CallConnect      CallKind = "Connect"
+	CallResetSession CallKind = "ResetSession"
+)
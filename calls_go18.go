@@ -0,0 +1,107 @@
+//go:build go1.8
+// +build go1.8
+
+package sqlmock
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Call is a single driver call received by the mock.
+type Call struct {
+	// Seq is the position of the call in the history, starting from 1
+	Seq int
+	// Kind is the driver method which was called
+	Kind CallKind
+	// Conn is the ID of the connection which received the call,
+	// connections are numbered from 1 in the order they were opened,
+	// including the ones which failed to open
+	Conn int
+	// SQL is the query of Query, Exec and Prepare calls
+	SQL string
+	// Args are the arguments of Query and Exec calls
+	Args []driver.NamedValue
+	// Time is when the call returned
+	Time time.Time
+	// Expectation is the expectation matched by the call,
+	// nil if the call was not expected
+	Expectation fmt.Stringer
+	// Err is the error returned to the caller
+	Err error
+
+	rejected bool // whether the call was rejected, see UnexpectedCalls
+}
+
+// String returns string representation
+func (c Call) String() string {
+	msg := fmt.Sprintf("#%d %s", c.Seq, c.Kind)
+	if c.SQL != "" {
+		msg += fmt.Sprintf(" '%s'", stripQuery(c.SQL))
+	}
+	if len(c.Args) > 0 {
+		args := make([]string, len(c.Args))
+		for i, arg := range c.Args {
+			args[i] = fmt.Sprintf("%+v", arg.Value)
+			if arg.Name != "" {
+				args[i] = arg.Name + "=" + args[i]
+			}
+		}
+		msg += " with args [" + strings.Join(args, ", ") + "]"
+	}
+	if c.Expectation == nil {
+		msg += ", not expected"
+	}
+	if c.Err != nil {
+		msg += fmt.Sprintf(", returned error: %s", c.Err)
+	}
+	return msg
+}
+
+// Calls returns the history of driver calls received by the mock,
+// in the order they were completed.
+func (c *sqlmock) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	calls := make([]Call, len(c.calls))
+	copy(calls, c.calls)
+	return calls
+}
+
+// UnexpectedCalls returns the calls which did not match any
+// expectation and were rejected, in the order they were received.
+// Unexpected closes are left out unless any close was expected,
+// as they are not rejected, see Close.
+func (c *sqlmock) UnexpectedCalls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var calls []Call
+	for _, call := range c.calls {
+		if call.rejected {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// record appends a call to the history, it is meant to be deferred
+// when the call is received, so that err holds the returned error
+func (c *conn) record(kind CallKind, query string, args []driver.NamedValue, ex expectation, err *error) {
+	call := Call{Kind: kind, Conn: c.id, SQL: query, Args: args, Time: time.Now()}
+	// expectations are pointers, a nil one must not be recorded as matched
+	if ex != nil && !reflect.ValueOf(ex).IsNil() {
+		call.Expectation = ex
+	}
+	if err != nil {
+		call.Err = *err
+	}
+	// an unexpected close is rejected only if closes were expected
+	call.rejected = call.Expectation == nil && (kind != CallClose || c.expectsClose())
+	c.mu.Lock()
+	call.Seq = len(c.calls) + 1
+	c.calls = append(c.calls, call)
+	c.mu.Unlock()
+}
@@ -0,0 +1,163 @@
+//go:build go1.8
+// +build go1.8
+
+package sqlmock
+
+import (
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestCallsHistory(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	begin := mock.ExpectBegin()
+	query := mock.ExpectQuery("SELECT name FROM users").
+		WithArgs(1).
+		WillReturnRows(NewRows([]string{"name"}).AddRow("john"))
+	mock.ExpectRollback().WillReturnError(errors.New("rollback failed"))
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+	var name string
+	if err := tx.QueryRow("SELECT name FROM users WHERE id = ?", 1).Scan(&name); err != nil {
+		t.Fatalf("unexpected error on query: %s", err)
+	}
+	_, _ = tx.Exec("DELETE FROM users")
+	_ = tx.Rollback()
+
+	calls := mock.Calls()
+	if len(calls) != 4 {
+		t.Fatalf("expected 4 calls to be recorded, but got %d: %v", len(calls), calls)
+	}
+
+	kinds := []CallKind{CallBegin, CallQuery, CallExec, CallRollback}
+	for i, call := range calls {
+		if call.Seq != i+1 {
+			t.Errorf("call %d: expected sequence number %d, but got %d", i, i+1, call.Seq)
+		}
+		if call.Kind != kinds[i] {
+			t.Errorf("call %d: expected kind %s, but got %s", i, kinds[i], call.Kind)
+		}
+		if call.Time.IsZero() {
+			t.Errorf("call %d: expected time to be set", i)
+		}
+	}
+
+	if calls[0].Expectation != begin {
+		t.Errorf("expected begin call to match begin expectation, but got: %v", calls[0].Expectation)
+	}
+	if calls[1].Expectation != query {
+		t.Errorf("expected query call to match query expectation, but got: %v", calls[1].Expectation)
+	}
+	if calls[1].SQL != "SELECT name FROM users WHERE id = ?" || len(calls[1].Args) != 1 || calls[1].Args[0].Value != int64(1) {
+		t.Errorf("unexpected query call recorded: %s", calls[1])
+	}
+	if calls[2].Expectation != nil || calls[2].Err == nil {
+		t.Errorf("expected exec call to be recorded as not expected, but got: %s", calls[2])
+	}
+	if calls[3].Err == nil || calls[3].Err.Error() != "rollback failed" {
+		t.Errorf("expected rollback call to record returned error, but got: %v", calls[3].Err)
+	}
+}
+
+func TestCallsHistoryConcurrent(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO users").WillReturnResult(NewResult(1, 1)).Times(10)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = db.Exec("INSERT INTO users(name) VALUES (?)", "john")
+		}()
+	}
+	wg.Wait()
+
+	calls := mock.Calls()
+	if len(calls) != 10 {
+		t.Fatalf("expected 10 calls to be recorded, but got %d", len(calls))
+	}
+	for i, call := range calls {
+		if call.Seq != i+1 {
+			t.Errorf("call %d: expected sequence number %d, but got %d", i, i+1, call.Seq)
+		}
+	}
+}
+
+func TestCallString(t *testing.T) {
+	call := Call{Seq: 2, Kind: CallQuery, SQL: "SELECT *\n  FROM users WHERE id = ?", Err: errors.New("fail")}
+	call.Args = convertValueToNamedValue([]driver.Value{int64(5)})
+
+	expected := "#2 Query 'SELECT * FROM users WHERE id = ?' with args [5], not expected, returned error: fail"
+	if call.String() != expected {
+		t.Errorf("expected %q, but got %q", expected, call.String())
+	}
+}
+
+func TestUnexpectedCalls(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO users").WillReturnResult(NewResult(1, 1))
+
+	// errors are ignored on purpose, as the code under test might do
+	_, _ = db.Query("SELECT name FROM users")
+	_, _ = db.Exec("INSERT INTO users(name) VALUES (?)", "john")
+	_, _ = db.Exec("DELETE FROM users WHERE id = ?", 1)
+
+	calls := mock.UnexpectedCalls()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 unexpected calls, but got %d: %v", len(calls), calls)
+	}
+	if calls[0].Kind != CallQuery || calls[1].Kind != CallExec {
+		t.Errorf("expected unexpected query and exec calls, but got: %v", calls)
+	}
+	if calls[1].SQL != "DELETE FROM users WHERE id = ?" {
+		t.Errorf("unexpected sql recorded: %s", calls[1].SQL)
+	}
+
+	var report *ExpectationsError
+	if err := mock.ExpectationsWereMet(); !errors.As(err, &report) || len(report.Unexpected) != 2 {
+		t.Errorf("expected unexpected calls to be reported, but got: %v", err)
+	}
+}
+
+func TestUnexpectedCallsWithoutIdleCloses(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db.Close()
+
+	if calls := mock.Calls(); len(calls) != 1 || calls[0].Kind != CallClose {
+		t.Fatalf("expected the close to be recorded, but got: %v", calls)
+	}
+	if calls := mock.UnexpectedCalls(); len(calls) != 0 {
+		t.Errorf("expected the close not to be reported as unexpected, but got: %v", calls)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
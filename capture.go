@@ -0,0 +1,102 @@
+package sqlmock
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Capture is an Argument, which matches any argument and records
+// the values it was given, once the expectation is triggered.
+// It is useful for the values which are not known up front, like
+// generated identifiers or timestamps:
+//
+//	id := sqlmock.NewCapture()
+//	mock.ExpectExec("INSERT INTO users").WithArgs(id, "john")
+//	mock.ExpectExec("INSERT INTO audit").WithArgs(id.Same(), "created")
+//
+// The values are recorded only when the expectation is met, a
+// call rejected for any other reason does not leave a value.
+type Capture struct {
+	mu     sync.Mutex
+	values []driver.Value
+}
+
+// NewCapture creates an empty Capture
+func NewCapture() *Capture {
+	return &Capture{}
+}
+
+// Match implements the Argument interface, it matches any argument
+func (c *Capture) Match(_ driver.Value) bool {
+	return true
+}
+
+// String implements the ArgumentDescriber interface
+func (c *Capture) String() string {
+	return "any argument, captured"
+}
+
+func (c *Capture) record(v driver.Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values = append(c.values, v)
+}
+
+// Value returns the value captured last,
+// or nil if nothing was captured yet
+func (c *Capture) Value() driver.Value {
+	v, _ := c.last()
+	return v
+}
+
+// Values returns all the captured values, in the order
+// the expectations were triggered
+func (c *Capture) Values() []driver.Value {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	values := make([]driver.Value, len(c.values))
+	copy(values, c.values)
+	return values
+}
+
+// Matches reports whether both captures captured a value
+// and their values captured last are equal
+func (c *Capture) Matches(other *Capture) bool {
+	v, ok := c.last()
+	if !ok {
+		return false
+	}
+	w, ok := other.last()
+	return ok && reflect.DeepEqual(v, w)
+}
+
+// Same will return an Argument, which matches an argument
+// equal to the value captured last, at the time the argument
+// is matched. It does not match anything until a value is
+// captured.
+func (c *Capture) Same() Argument {
+	return describedArgument{
+		desc: "same as the captured argument",
+		match: func(v driver.Value) error {
+			captured, ok := c.last()
+			switch {
+			case !ok:
+				return fmt.Errorf("nothing was captured yet")
+			case !reflect.DeepEqual(captured, v):
+				return fmt.Errorf("captured [%T - %+v]", captured, captured)
+			}
+			return nil
+		},
+	}
+}
+
+func (c *Capture) last() (driver.Value, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.values) == 0 {
+		return nil, false
+	}
+	return c.values[len(c.values)-1], true
+}
@@ -0,0 +1,116 @@
+package sqlmock
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCaptureSameArgument(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	id := NewCapture()
+	mock.ExpectExec("INSERT INTO users").WithArgs(id, "john").WillReturnResult(NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO audit").WithArgs(id.Same(), "created").WillReturnResult(NewResult(1, 1))
+
+	if id.Value() != nil {
+		t.Errorf("expected no value to be captured yet, but got %v", id.Value())
+	}
+
+	if _, err = db.Exec("INSERT INTO users(id, name) VALUES (?, ?)", "6ba7b810", "john"); err != nil {
+		t.Errorf("error '%s' was not expected, while inserting a row", err)
+	}
+	if _, err = db.Exec("INSERT INTO audit(user_id, action) VALUES (?, ?)", "6ba7b810", "created"); err != nil {
+		t.Errorf("error '%s' was not expected, while inserting an audit row", err)
+	}
+
+	if id.Value() != "6ba7b810" {
+		t.Errorf("expected captured value to be 6ba7b810, but got %v", id.Value())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCaptureSameArgumentMismatch(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	id := NewCapture()
+	mock.ExpectExec("DELETE FROM audit").WithArgs(id.Same()).WillReturnResult(NewResult(0, 1))
+
+	_, err = db.Exec("DELETE FROM audit WHERE user_id = ?", 5)
+	if err == nil || !strings.HasSuffix(err.Error(), "argument 0 expected same as the captured argument does not match actual [int64 - 5]: nothing was captured yet") {
+		t.Errorf("expected an error, since nothing was captured, but got: %v", err)
+	}
+
+	id.record(int64(4))
+	_, err = db.Exec("DELETE FROM audit WHERE user_id = ?", 5)
+	if err == nil || !strings.HasSuffix(err.Error(), "captured [int64 - 4]") {
+		t.Errorf("expected an error, since another value was captured, but got: %v", err)
+	}
+}
+
+func TestCaptureRecordsTriggeredCallsOnly(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	created := NewCapture()
+	mock.ExpectQuery("SELECT id FROM users").
+		WithArgs(created, "john").
+		WillReturnRows(NewRows([]string{"id"}).AddRow(1)).
+		Times(2)
+
+	// the second argument does not match, nothing is captured
+	if _, err = db.Query("SELECT id FROM users WHERE created > ? AND name = ?", 10, "jane"); err == nil {
+		t.Error("expected an error, since the name does not match")
+	}
+	for _, v := range []int{10, 20} {
+		rows, err := db.Query("SELECT id FROM users WHERE created > ? AND name = ?", v, "john")
+		if err != nil {
+			t.Errorf("error '%s' was not expected, while querying rows", err)
+			continue
+		}
+		rows.Close()
+	}
+
+	if expected := []driver.Value{int64(10), int64(20)}; !reflect.DeepEqual(created.Values(), expected) {
+		t.Errorf("expected captured values to be %v, but got %v", expected, created.Values())
+	}
+	if created.Value() != int64(20) {
+		t.Errorf("expected last captured value to be 20, but got %v", created.Value())
+	}
+}
+
+func TestCaptureMatches(t *testing.T) {
+	t.Parallel()
+	a, b := NewCapture(), NewCapture()
+	if a.Matches(b) {
+		t.Error("expected empty captures not to match")
+	}
+
+	a.record("x")
+	if a.Matches(b) {
+		t.Error("expected capture not to match an empty one")
+	}
+
+	b.record("y")
+	b.record("x")
+	if !a.Matches(b) || !b.Matches(a) {
+		t.Error("expected captures with the same last value to match")
+	}
+}
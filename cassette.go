@@ -0,0 +1,604 @@
+//go:build go1.10
+// +build go1.10
+
+package sqlmock
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// RecordEnv is the environment variable, which makes Cassette record
+// the cassette again with the real driver, when it is set to a non
+// empty value, as in SQLMOCK_RECORD=1 go test ./...
+const RecordEnv = "SQLMOCK_RECORD"
+
+// Recorder is a driver.Driver, which proxies the calls to a real driver
+// and records the queries, their arguments, the returned rows with their
+// column types, the exec results, the transactions and the errors as a
+// cassette. The cassette is a script read by Sqlmock.LoadExpectations,
+// so that it is replayed with the expectations of the recorded calls.
+//
+// The calls are recorded in the order they are made, so the database
+// should be used by a single goroutine while recording. Only the first
+// result set of the rows is recorded.
+type Recorder struct {
+	drv driver.Driver
+
+	mu    sync.Mutex
+	steps []*recordedStep
+}
+
+// recordedStep is a recorded expectation, with
+// the rows of a query read while recording
+type recordedStep struct {
+	step scriptStep
+	rows *recordedRows
+}
+
+// NewRecorder returns a Recorder proxying the calls to the driver.
+func NewRecorder(drv driver.Driver) *Recorder {
+	return &Recorder{drv: drv}
+}
+
+// Open meets https://golang.org/pkg/database/sql/driver/#Driver
+func (r *Recorder) Open(dsn string) (driver.Conn, error) {
+	cn, err := r.drv.Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingConn{r: r, conn: cn}, nil
+}
+
+// OpenDB opens the database with the real driver
+// and the given DSN, recording the calls made.
+func (r *Recorder) OpenDB(dsn string) *sql.DB {
+	return sql.OpenDB(recordingConnector{r: r, dsn: dsn})
+}
+
+// WriteTo writes the cassette of the calls recorded so far as JSON.
+func (r *Recorder) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	s := script{Expectations: make([]scriptStep, len(r.steps))}
+	for i, rs := range r.steps {
+		s.Expectations[i] = rs.step
+		if rs.rows == nil {
+			continue
+		}
+		if err := rs.rows.fill(&s.Expectations[i]); err != nil {
+			r.mu.Unlock()
+			return 0, fmt.Errorf("expectation %d (%s): %s", i+1, rs.step.Type, err)
+		}
+	}
+	r.mu.Unlock()
+
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(b, '\n'))
+	return int64(n), err
+}
+
+// Save writes the cassette to the file at path,
+// creating its directory if it does not exist.
+func (r *Recorder) Save(path string) error {
+	var buf bytes.Buffer
+	if _, err := r.WriteTo(&buf); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// record adds the step, unless the call failed with driver.ErrBadConn,
+// which database/sql retries on another connection
+func (r *Recorder) record(step scriptStep, err error, rows *recordedRows) {
+	if errors.Is(err, driver.ErrBadConn) {
+		return
+	}
+	if err != nil {
+		step.Error = err.Error()
+	}
+	r.mu.Lock()
+	r.steps = append(r.steps, &recordedStep{step: step, rows: rows})
+	r.mu.Unlock()
+}
+
+// Cassette opens a database for the cassette file at path. If the
+// cassette exists and RecordEnv is not set, the database is a mock
+// replaying the cassette, which matches the queries by QueryMatcherEqual
+// unless the options set another QueryMatcher. Otherwise, the database
+// is opened with the real driver and the DSN, and the calls are recorded.
+//
+// The returned function must be called once the database is not needed:
+// when replaying, it closes the database and returns an error if not all
+// of the cassette was replayed, when recording, it closes the database
+// and saves the cassette.
+//
+//	db, done, err := sqlmock.Cassette("testdata/users.json", &sqlite3.SQLiteDriver{}, ":memory:")
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer func() {
+//		if err := done(); err != nil {
+//			t.Error(err)
+//		}
+//	}()
+func Cassette(path string, drv driver.Driver, dsn string, options ...SqlMockOption) (*sql.DB, func() error, error) {
+	_, statErr := os.Stat(path)
+	if os.Getenv(RecordEnv) == "" && statErr == nil {
+		return replayCassette(path, options)
+	}
+
+	r := NewRecorder(drv)
+	db := r.OpenDB(dsn)
+	done := func() error {
+		if err := db.Close(); err != nil {
+			return err
+		}
+		return r.Save(path)
+	}
+	return db, done, nil
+}
+
+// replayCassette opens a mock database with the expectations of the cassette
+func replayCassette(path string, options []SqlMockOption) (*sql.DB, func() error, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	options = append([]SqlMockOption{QueryMatcherOption(QueryMatcherEqual)}, options...)
+	db, mock, err := New(options...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := mock.LoadExpectations(f); err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("%s: %s", path, err)
+	}
+	done := func() error {
+		err := mock.ExpectationsWereMet()
+		mock.ExpectClose()
+		db.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %s", path, err)
+		}
+		return nil
+	}
+	return db, done, nil
+}
+
+// recordingConnector opens the recorded connections to the real database
+type recordingConnector struct {
+	r   *Recorder
+	dsn string
+}
+
+// Connect meets https://golang.org/pkg/database/sql/driver/#Connector
+func (c recordingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.r.Open(c.dsn)
+}
+
+// Driver meets https://golang.org/pkg/database/sql/driver/#Connector
+func (c recordingConnector) Driver() driver.Driver {
+	return c.r
+}
+
+// recordingConn proxies a connection of the real driver
+type recordingConn struct {
+	r    *Recorder
+	conn driver.Conn
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *recordingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.prepare(ctx, query)
+	c.r.record(scriptStep{Type: "prepare", SQL: query}, err, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingStmt{r: c.r, query: query, stmt: stmt}, nil
+}
+
+// prepare prepares the statement with the real connection
+func (c *recordingConn) prepare(ctx context.Context, query string) (driver.Stmt, error) {
+	if pc, ok := c.conn.(driver.ConnPrepareContext); ok {
+		return pc.PrepareContext(ctx, query)
+	}
+	return c.conn.Prepare(query)
+}
+
+func (c *recordingConn) Close() error {
+	return c.conn.Close()
+}
+
+// Ping meets https://golang.org/pkg/database/sql/driver/#Pinger,
+// the pings are not recorded, since the mock expects them only
+// if they are monitored
+func (c *recordingConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession meets https://golang.org/pkg/database/sql/driver/#SessionResetter
+func (c *recordingConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+// IsValid meets https://golang.org/pkg/database/sql/driver/#Validator
+func (c *recordingConn) IsValid() bool {
+	if validator, ok := c.conn.(interface{ IsValid() bool }); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *recordingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	var tx driver.Tx
+	var err error
+	if bc, ok := c.conn.(driver.ConnBeginTx); ok {
+		tx, err = bc.BeginTx(ctx, opts)
+	} else {
+		tx, err = c.conn.Begin()
+	}
+	c.r.record(scriptStep{Type: "begin"}, err, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingTx{r: c.r, tx: tx}, nil
+}
+
+// CheckNamedValue meets https://golang.org/pkg/database/sql/driver/#NamedValueChecker
+func (c *recordingConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// QueryContext runs the query with the real connection, or prepares it if the
+// connection cannot query directly, since the mock would not expect a prepare
+func (c *recordingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	var rows driver.Rows
+	err := driver.ErrSkip
+	if qc, ok := c.conn.(driver.QueryerContext); ok {
+		rows, err = qc.QueryContext(ctx, query, args)
+	}
+	var stmt driver.Stmt
+	if errors.Is(err, driver.ErrSkip) {
+		if stmt, err = c.prepare(ctx, query); err == nil {
+			rows, err = stmtQuery(ctx, stmt, args)
+		}
+	}
+	rows, err = c.r.recordQuery(query, args, rows, err)
+	if stmt != nil {
+		if err != nil {
+			stmt.Close()
+		} else {
+			rows.(*recordingRows).stmt = stmt
+		}
+	}
+	return rows, err
+}
+
+// ExecContext runs the query with the real connection, or prepares it if the
+// connection cannot exec directly, since the mock would not expect a prepare
+func (c *recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	var res driver.Result
+	err := driver.ErrSkip
+	if ec, ok := c.conn.(driver.ExecerContext); ok {
+		res, err = ec.ExecContext(ctx, query, args)
+	}
+	if errors.Is(err, driver.ErrSkip) {
+		var stmt driver.Stmt
+		if stmt, err = c.prepare(ctx, query); err == nil {
+			res, err = stmtExec(ctx, stmt, args)
+			stmt.Close()
+		}
+	}
+	return c.r.recordExec(query, args, res, err)
+}
+
+// recordingTx proxies a transaction of the real driver
+type recordingTx struct {
+	r  *Recorder
+	tx driver.Tx
+}
+
+func (tx *recordingTx) Commit() error {
+	err := tx.tx.Commit()
+	tx.r.record(scriptStep{Type: "commit"}, err, nil)
+	return err
+}
+
+func (tx *recordingTx) Rollback() error {
+	err := tx.tx.Rollback()
+	tx.r.record(scriptStep{Type: "rollback"}, err, nil)
+	return err
+}
+
+// recordingStmt proxies a prepared statement of the real driver, its
+// queries and execs are recorded as if they were not prepared, since
+// the mock matches them by the query of the statement
+type recordingStmt struct {
+	r     *Recorder
+	query string
+	stmt  driver.Stmt
+}
+
+func (s *recordingStmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *recordingStmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+// CheckNamedValue meets https://golang.org/pkg/database/sql/driver/#NamedValueChecker
+func (s *recordingStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// Deprecated: Drivers should implement StmtExecContext instead (or additionally).
+func (s *recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+// Deprecated: Drivers should implement StmtQueryContext instead (or additionally).
+func (s *recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *recordingStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	res, err := stmtExec(ctx, s.stmt, args)
+	return s.r.recordExec(s.query, args, res, err)
+}
+
+func (s *recordingStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := stmtQuery(ctx, s.stmt, args)
+	return s.r.recordQuery(s.query, args, rows, err)
+}
+
+// stmtQuery runs the query of the statement of the real driver
+func stmtQuery(ctx context.Context, stmt driver.Stmt, args []driver.NamedValue) (driver.Rows, error) {
+	if sc, ok := stmt.(driver.StmtQueryContext); ok {
+		return sc.QueryContext(ctx, args)
+	}
+	values, err := plainValues(args)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Query(values)
+}
+
+// stmtExec runs the exec of the statement of the real driver
+func stmtExec(ctx context.Context, stmt driver.Stmt, args []driver.NamedValue) (driver.Result, error) {
+	if sc, ok := stmt.(driver.StmtExecContext); ok {
+		return sc.ExecContext(ctx, args)
+	}
+	values, err := plainValues(args)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Exec(values)
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return named
+}
+
+func plainValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, fmt.Errorf("sql: driver does not support the use of Named Parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+// recordQuery records the query, the returned rows are recorded as they are read
+func (r *Recorder) recordQuery(query string, args []driver.NamedValue, rows driver.Rows, err error) (driver.Rows, error) {
+	step, aerr := recordedArgs(scriptStep{Type: "query", SQL: query}, args)
+	if aerr != nil {
+		if err == nil {
+			rows.Close()
+		}
+		return nil, aerr
+	}
+	if err != nil {
+		r.record(step, err, nil)
+		return nil, err
+	}
+	rr := &recordingRows{Rows: rows, recorded: &recordedRows{columns: rows.Columns()}}
+	if tn, ok := rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		rr.recorded.types = make([]string, len(rr.recorded.columns))
+		for i := range rr.recorded.types {
+			rr.recorded.types[i] = tn.ColumnTypeDatabaseTypeName(i)
+		}
+	}
+	r.record(step, nil, rr.recorded)
+	return rr, nil
+}
+
+// recordExec records the exec with its result
+func (r *Recorder) recordExec(query string, args []driver.NamedValue, res driver.Result, err error) (driver.Result, error) {
+	step, aerr := recordedArgs(scriptStep{Type: "exec", SQL: query}, args)
+	if aerr != nil {
+		return nil, aerr
+	}
+	if err == nil {
+		step.Result = &scriptResult{}
+		step.Result.LastInsertID, _ = res.LastInsertId()
+		step.Result.RowsAffected, _ = res.RowsAffected()
+	}
+	r.record(step, err, nil)
+	return res, err
+}
+
+// recordedArgs sets the arguments of the step, time and
+// bytes arguments are recorded as their script matchers
+func recordedArgs(step scriptStep, args []driver.NamedValue) (scriptStep, error) {
+	if len(args) == 0 {
+		step.NoArgs = true
+		return step, nil
+	}
+	step.Args = make([]json.RawMessage, len(args))
+	for i, arg := range args {
+		var v interface{} = arg.Value
+		switch value := arg.Value.(type) {
+		case time.Time:
+			v = map[string]time.Time{"time": value}
+		case []byte:
+			v = map[string][]byte{"bytes": value}
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return step, fmt.Errorf("recording argument %d: %s", i, err)
+		}
+		step.Args[i] = b
+	}
+	return step, nil
+}
+
+// recordedRows are the rows read by a recorded query
+type recordedRows struct {
+	mu      sync.Mutex
+	columns []string
+	types   []string
+	values  [][]driver.Value
+}
+
+// fill sets the columns, their types and the rows of the step
+func (rr *recordedRows) fill(step *scriptStep) error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	step.Columns = rr.columns
+	step.ColumnTypes = rr.types
+	step.ScanTypes = make([]string, len(rr.columns))
+	rows := make([][]interface{}, len(rr.values))
+	for n, values := range rr.values {
+		rows[n] = make([]interface{}, len(values))
+		for i, v := range values {
+			if name := recordedScanType(v); name != "" && step.ScanTypes[i] == "" {
+				step.ScanTypes[i] = name
+			}
+			switch value := v.(type) {
+			case []byte:
+				rows[n][i] = map[string][]byte{"bytes": value}
+			default:
+				rows[n][i] = value
+			}
+		}
+	}
+
+	b, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+	step.Rows = b
+	return nil
+}
+
+// recordedScanType returns the script scan type of the value, by the type
+// of the first non NULL value in a column the rows are replayed as read
+func recordedScanType(v driver.Value) string {
+	if v == nil {
+		return ""
+	}
+	for name, sample := range scriptScanTypes {
+		if sample != nil && reflect.TypeOf(v) == reflect.TypeOf(sample) {
+			return name
+		}
+	}
+	return ""
+}
+
+// recordingRows proxies the rows of the real driver and records the values
+// read, the statement prepared for a query is closed with the rows
+type recordingRows struct {
+	driver.Rows
+	recorded *recordedRows
+	stmt     driver.Stmt
+}
+
+func (rs *recordingRows) Close() error {
+	err := rs.Rows.Close()
+	if rs.stmt != nil {
+		if serr := rs.stmt.Close(); err == nil {
+			err = serr
+		}
+	}
+	return err
+}
+
+func (rs *recordingRows) Next(dest []driver.Value) error {
+	if err := rs.Rows.Next(dest); err != nil {
+		return err
+	}
+	values := make([]driver.Value, len(dest))
+	for i, v := range dest {
+		if b, ok := v.([]byte); ok {
+			v = append([]byte(nil), b...) // the driver may reuse the buffer
+		}
+		values[i] = v
+	}
+	rs.recorded.mu.Lock()
+	rs.recorded.values = append(rs.recorded.values, values)
+	rs.recorded.mu.Unlock()
+	return nil
+}
+
+// ColumnTypeDatabaseTypeName meets https://golang.org/pkg/database/sql/driver/#RowsColumnTypeDatabaseTypeName
+func (rs *recordingRows) ColumnTypeDatabaseTypeName(index int) string {
+	if rs.recorded.types == nil {
+		return ""
+	}
+	return rs.recorded.types[index]
+}
+
+// ColumnTypeScanType meets https://golang.org/pkg/database/sql/driver/#RowsColumnTypeScanType
+func (rs *recordingRows) ColumnTypeScanType(index int) reflect.Type {
+	if st, ok := rs.Rows.(driver.RowsColumnTypeScanType); ok {
+		return st.ColumnTypeScanType(index)
+	}
+	return reflect.TypeOf(new(interface{})).Elem()
+}
@@ -0,0 +1,349 @@
+//go:build go1.10
+// +build go1.10
+
+package sqlmock
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type cassetteUser struct {
+	ID      int64
+	Name    string
+	Avatar  []byte
+	Created time.Time
+}
+
+// useCassetteUsers runs the calls recorded and replayed by the cassette tests
+func useCassetteUsers(db *sql.DB) (cassetteUser, int64, error) {
+	var u cassetteUser
+	tx, err := db.Begin()
+	if err != nil {
+		return u, 0, err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRow("SELECT id, name, avatar, created FROM users WHERE id = ?", 1)
+	if err := row.Scan(&u.ID, &u.Name, &u.Avatar, &u.Created); err != nil {
+		return u, 0, err
+	}
+
+	stmt, err := tx.Prepare("UPDATE users SET name = ?, avatar = ?, updated = ? WHERE id = ?")
+	if err != nil {
+		return u, 0, err
+	}
+	defer stmt.Close()
+	res, err := stmt.Exec("jane", []byte{0x1, 0x2}, u.Created.Add(time.Hour), u.ID)
+	if err != nil {
+		return u, 0, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return u, 0, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM sessions WHERE user_id = ?", u.ID); err == nil {
+		return u, 0, fmt.Errorf("expected the delete to fail")
+	}
+	return u, affected, tx.Commit()
+}
+
+// realCassetteDriver returns a mock used as the real driver to record,
+// expecting the calls of useCassetteUsers
+func realCassetteDriver(t *testing.T, dsn string, created time.Time) (*sql.DB, Sqlmock) {
+	db, mock, err := NewWithDSN(dsn)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := NewRowsWithColumnDefinition(
+		NewColumn("id").OfType("INTEGER", int64(0)),
+		NewColumn("name").OfType("VARCHAR", ""),
+		NewColumn("avatar").OfType("BLOB", []byte(nil)),
+		NewColumn("created").OfType("TIMESTAMP", time.Time{}),
+	).AddRow(1, "john", []byte("png"), created)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM users").WithArgs(1).WillReturnRows(rows)
+	mock.ExpectPrepare("UPDATE users").
+		ExpectExec().
+		WithArgs("jane", []byte{0x1, 0x2}, created.Add(time.Hour), 1).
+		WillReturnResult(NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM sessions").WithArgs(1).WillReturnError(errors.New("constraint failed"))
+	mock.ExpectCommit()
+	mock.ExpectClose()
+	return db, mock
+}
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2020, 3, 4, 5, 6, 7, 8000, time.UTC)
+	realDB, real := realCassetteDriver(t, "cassette_record_and_replay", created)
+	defer realDB.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "users.json")
+	db, done, err := Cassette(path, realDB.Driver(), "cassette_record_and_replay")
+	if err != nil {
+		t.Fatalf("unexpected error opening the cassette to record: %s", err)
+	}
+	recorded, affected, err := useCassetteUsers(db)
+	if err != nil {
+		t.Fatalf("unexpected error while recording: %s", err)
+	}
+	if err := done(); err != nil {
+		t.Fatalf("unexpected error saving the cassette: %s", err)
+	}
+	if err := real.ExpectationsWereMet(); err != nil {
+		t.Fatalf("the recorded calls did not reach the real driver: %s", err)
+	}
+	if affected != 1 || recorded.Name != "john" || !recorded.Created.Equal(created) {
+		t.Fatalf("unexpected recorded results: %+v, %d rows affected", recorded, affected)
+	}
+
+	db, done, err = Cassette(path, nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error opening the cassette to replay: %s", err)
+	}
+	replayed, affected, err := useCassetteUsers(db)
+	if err != nil {
+		t.Fatalf("unexpected error while replaying: %s", err)
+	}
+	if err := done(); err != nil {
+		t.Errorf("unexpected error, the whole cassette should be replayed: %s", err)
+	}
+	if affected != 1 {
+		t.Errorf("expected 1 row affected on replay, but got %d", affected)
+	}
+	if !reflect.DeepEqual(replayed.Avatar, recorded.Avatar) || replayed.ID != recorded.ID ||
+		replayed.Name != recorded.Name || !replayed.Created.Equal(recorded.Created) {
+		t.Errorf("expected the replayed user %+v to be the recorded %+v", replayed, recorded)
+	}
+}
+
+func TestRecorderWritesColumnTypes(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC)
+	realDB, _ := realCassetteDriver(t, "cassette_column_types", created)
+	defer realDB.Close()
+
+	r := NewRecorder(realDB.Driver())
+	db := r.OpenDB("cassette_column_types")
+	if _, _, err := useCassetteUsers(db); err != nil {
+		t.Fatalf("unexpected error while recording: %s", err)
+	}
+	db.Close()
+
+	var buf bytes.Buffer
+	if _, err := r.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error writing the cassette: %s", err)
+	}
+	var s script
+	if err := json.Unmarshal(buf.Bytes(), &s); err != nil {
+		t.Fatalf("unexpected error reading the cassette: %s", err)
+	}
+
+	var types []string
+	for _, step := range s.Expectations {
+		types = append(types, step.Type)
+	}
+	if exp := []string{"begin", "query", "prepare", "exec", "exec", "commit"}; !reflect.DeepEqual(types, exp) {
+		t.Fatalf("expected the recorded types %v, but got %v", exp, types)
+	}
+
+	query := s.Expectations[1]
+	if exp := []string{"INTEGER", "VARCHAR", "BLOB", "TIMESTAMP"}; !reflect.DeepEqual(query.ColumnTypes, exp) {
+		t.Errorf("expected the column types %v, but got %v", exp, query.ColumnTypes)
+	}
+	if exp := []string{"int64", "string", "bytes", "time"}; !reflect.DeepEqual(query.ScanTypes, exp) {
+		t.Errorf("expected the scan types %v, but got %v", exp, query.ScanTypes)
+	}
+	if exp, rows := `[[1,"john",{"bytes":"cG5n"},"2020-03-04T05:06:07Z"]]`, compactJSON(query.Rows); rows != exp {
+		t.Errorf("expected the rows %s, but got %s", exp, rows)
+	}
+
+	update := s.Expectations[3]
+	if update.SQL != "UPDATE users SET name = ?, avatar = ?, updated = ? WHERE id = ?" {
+		t.Errorf("expected the statement exec to be recorded with the prepared query, but got %q", update.SQL)
+	}
+	if len(update.Args) != 4 || compactJSON(update.Args[1]) != `{"bytes":"AQI="}` ||
+		compactJSON(update.Args[2]) != `{"time":"2020-03-04T06:06:07Z"}` {
+		t.Errorf("unexpected recorded arguments %s", update.Args)
+	}
+	if update.Result == nil || update.Result.RowsAffected != 1 {
+		t.Errorf("expected the result to be recorded, but got %+v", update.Result)
+	}
+	if s.Expectations[4].Error != "constraint failed" {
+		t.Errorf("expected the error to be recorded, but got %q", s.Expectations[4].Error)
+	}
+}
+
+func TestCassetteReplaysBinaryValues(t *testing.T) {
+	t.Parallel()
+	blob := []byte{0xff, 0x00, 0xfe}
+	realDB, real, err := NewWithDSN("cassette_binary_values")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer realDB.Close()
+	real.ExpectQuery("SELECT data FROM blobs").
+		WillReturnRows(NewRowsWithColumnDefinition(NewColumn("data").OfType("BLOB", []byte(nil))).AddRow(blob))
+	real.ExpectClose()
+
+	path := filepath.Join(t.TempDir(), "blobs.json")
+	db, done, err := Cassette(path, realDB.Driver(), "cassette_binary_values")
+	if err != nil {
+		t.Fatalf("unexpected error opening the cassette to record: %s", err)
+	}
+	var recorded []byte
+	if err := db.QueryRow("SELECT data FROM blobs").Scan(&recorded); err != nil {
+		t.Fatalf("unexpected error while recording: %s", err)
+	}
+	if err := done(); err != nil {
+		t.Fatalf("unexpected error saving the cassette: %s", err)
+	}
+
+	db, done, err = Cassette(path, nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error opening the cassette to replay: %s", err)
+	}
+	var replayed []byte
+	if err := db.QueryRow("SELECT data FROM blobs").Scan(&replayed); err != nil {
+		t.Fatalf("unexpected error while replaying: %s", err)
+	}
+	if err := done(); err != nil {
+		t.Errorf("unexpected error, the whole cassette should be replayed: %s", err)
+	}
+	if !bytes.Equal(recorded, blob) || !bytes.Equal(replayed, blob) {
+		t.Errorf("expected the blob % x to be recorded and replayed, but got % x and % x", blob, recorded, replayed)
+	}
+}
+
+func compactJSON(raw json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
+
+func TestCassetteReplayReportsUnusedCalls(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "ping.json")
+	cassette := `{"expectations": [
+		{"type": "query", "sql": "SELECT 1", "no_args": true, "columns": ["1"], "rows": [[1]]},
+		{"type": "exec", "sql": "DELETE FROM users", "no_args": true, "result": {"rows_affected": 3}}
+	]}`
+	if err := ioutil.WriteFile(path, []byte(cassette), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	db, done, err := Cassette(path, nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error opening the cassette to replay: %s", err)
+	}
+	var one int
+	if err := db.QueryRow("SELECT 1").Scan(&one); err != nil {
+		t.Fatalf("unexpected error while replaying: %s", err)
+	}
+	err = done()
+	if err == nil || !strings.Contains(err.Error(), "DELETE FROM users") {
+		t.Errorf("expected the error to report the exec, which was not replayed, but got: %v", err)
+	}
+}
+
+func TestCassetteRecordsAgainWhenRequested(t *testing.T) {
+	// not parallel, since it sets the environment
+	created := time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC)
+	realDB, real := realCassetteDriver(t, "cassette_record_again", created)
+	defer realDB.Close()
+
+	path := filepath.Join(t.TempDir(), "users.json")
+	if err := ioutil.WriteFile(path, []byte(`{"expectations": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv(RecordEnv, "1")
+	defer os.Unsetenv(RecordEnv)
+
+	db, done, err := Cassette(path, realDB.Driver(), "cassette_record_again")
+	if err != nil {
+		t.Fatalf("unexpected error opening the cassette to record: %s", err)
+	}
+	if _, _, err := useCassetteUsers(db); err != nil {
+		t.Fatalf("unexpected error while recording: %s", err)
+	}
+	if err := done(); err != nil {
+		t.Fatalf("unexpected error saving the cassette: %s", err)
+	}
+	if err := real.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected the calls to be recorded with the real driver: %s", err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "SELECT id, name, avatar, created FROM users") {
+		t.Errorf("expected the cassette to be recorded again, but got %s", b)
+	}
+}
+
+func TestRecorderForwardsConnectionChecks(t *testing.T) {
+	t.Parallel()
+	realDB, real, err := NewWithDSN("cassette_connection_checks", MonitorPingsOption(true), MonitorSessionResetsOption(true))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer realDB.Close()
+
+	real.ExpectPing()
+	real.ExpectResetSession()
+	real.ExpectExec("UPDATE users").WillReturnBadConn()
+
+	r := NewRecorder(realDB.Driver())
+	cn, err := r.Open("cassette_connection_checks")
+	if err != nil {
+		t.Fatalf("unexpected error opening a recorded connection: %s", err)
+	}
+	ctx := context.Background()
+	if err := cn.(driver.Pinger).Ping(ctx); err != nil {
+		t.Errorf("unexpected error on ping: %s", err)
+	}
+	if err := cn.(driver.SessionResetter).ResetSession(ctx); err != nil {
+		t.Errorf("unexpected error on session reset: %s", err)
+	}
+
+	validator := cn.(interface{ IsValid() bool })
+	if !validator.IsValid() {
+		t.Error("expected the connection to be valid")
+	}
+	if _, err := cn.(driver.ExecerContext).ExecContext(ctx, "UPDATE users SET name = 'jane'", nil); err != driver.ErrBadConn {
+		t.Errorf("expected a bad connection, but got: %v", err)
+	}
+	if validator.IsValid() {
+		t.Error("expected the connection to be invalid, since the real one is broken")
+	}
+
+	if err := real.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected the checks to reach the real connection: %s", err)
+	}
+	var buf bytes.Buffer
+	if _, err := r.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error writing the cassette: %s", err)
+	}
+	if strings.Contains(buf.String(), "ping") || strings.Contains(buf.String(), "UPDATE") {
+		t.Errorf("expected neither the ping nor the bad connection to be recorded, but got %s", buf.String())
+	}
+}
@@ -0,0 +1,114 @@
+package sqlmock
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// conn is a connection opened by database/sql. All the connections
+// of a database share the expectations of its mock, but each of them
+// tracks the transaction it runs, since database/sql runs all the
+// statements of a transaction on the connection which started it.
+type conn struct {
+	*sqlmock
+	id       int                 // sequence number of the connection, starting from 1
+	expected *ExpectedConnection // expectation matched by the connection, if any
+	tx       *transaction        // open transaction
+	last     *transaction        // last finished transaction
+	bad      bool                // whether a call returned driver.ErrBadConn
+}
+
+// invalidate marks the connection as bad once a call on it returns
+// driver.ErrBadConn, it is meant to be deferred when the call is received
+func (c *conn) invalidate(err *error) {
+	if *err == driver.ErrBadConn {
+		c.bad = true
+	}
+}
+
+// transaction is the driver.Tx returned by every Begin
+type transaction struct {
+	conn       *conn
+	begin      *ExpectedBegin // expectation which started the transaction
+	state      string         // how the transaction was finished
+	savepoints []string       // savepoints created in the transaction
+}
+
+// finish ends the transaction on its connection, database/sql
+// considers it done even if Commit or Rollback fails
+func (tx *transaction) finish(state string) {
+	tx.state = state
+	if tx.conn.tx == tx {
+		tx.conn.tx = nil
+		tx.conn.last = tx
+	}
+}
+
+// scope defines the connection and the transaction
+// a statement is expected to run in
+type scope struct {
+	inTx       bool
+	outsideTx  bool
+	begin      *ExpectedBegin      // transaction started by this expectation
+	connection *ExpectedConnection // connection opened by this expectation
+}
+
+// check returns an error if a statement running
+// on the given connection does not meet the scope
+func (s *scope) check(cn *conn) error {
+	if s.connection != nil && cn.expected != s.connection {
+		return fmt.Errorf("expected to run on the connection opened by %s, but it ran on connection %d", s.connection, cn.id)
+	}
+
+	switch {
+	case s.begin != nil:
+		if cn.tx != nil && cn.tx.begin == s.begin {
+			return nil
+		}
+		if cn.tx == nil && cn.last != nil && cn.last.begin == s.begin {
+			return fmt.Errorf("expected to run in the transaction started by %s, but the transaction was already %s", s.begin, cn.last.state)
+		}
+		return fmt.Errorf("expected to run in the transaction started by %s, but it did not", s.begin)
+	case s.inTx && cn.tx == nil:
+		return fmt.Errorf("expected to run in a transaction, but it ran outside of any")
+	case s.outsideTx && cn.tx != nil:
+		return fmt.Errorf("expected to run outside of a transaction, but it ran in one")
+	}
+	return nil
+}
+
+// String returns string representation
+func (s *scope) String() string {
+	var msg []string
+	if s.connection != nil {
+		msg = append(msg, "runs on the connection opened by "+s.connection.String())
+	}
+	switch {
+	case s.begin != nil:
+		msg = append(msg, "runs in the transaction started by "+s.begin.String())
+	case s.inTx:
+		msg = append(msg, "runs in a transaction")
+	case s.outsideTx:
+		msg = append(msg, "runs outside of a transaction")
+	}
+	return strings.Join(msg, "\n  - ")
+}
+
+// sqlmock itself remains usable as a driver connection, every call
+// runs on a new connection without a transaction
+
+// Begin meets http://golang.org/pkg/database/sql/driver/#Conn interface
+func (c *sqlmock) Begin() (driver.Tx, error) {
+	return (&conn{sqlmock: c}).Begin()
+}
+
+// Close meets http://golang.org/pkg/database/sql/driver/#Conn interface
+func (c *sqlmock) Close() error {
+	return (&conn{sqlmock: c}).Close()
+}
+
+// Prepare meets http://golang.org/pkg/database/sql/driver/#Conn interface
+func (c *sqlmock) Prepare(query string) (driver.Stmt, error) {
+	return (&conn{sqlmock: c}).Prepare(query)
+}
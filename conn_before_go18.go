@@ -0,0 +1,16 @@
+//go:build !go1.8
+// +build !go1.8
+
+package sqlmock
+
+import "database/sql/driver"
+
+// Query meets http://golang.org/pkg/database/sql/driver/#Queryer
+func (c *sqlmock) Query(query string, args []driver.Value) (driver.Rows, error) {
+	return (&conn{sqlmock: c}).Query(query, args)
+}
+
+// Exec meets http://golang.org/pkg/database/sql/driver/#Execer
+func (c *sqlmock) Exec(query string, args []driver.Value) (driver.Result, error) {
+	return (&conn{sqlmock: c}).Exec(query, args)
+}
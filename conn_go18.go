@@ -0,0 +1,46 @@
+//go:build go1.8
+// +build go1.8
+
+package sqlmock
+
+import (
+	"context"
+	"database/sql/driver"
+)
+
+// Implement the "QueryerContext" interface
+func (c *sqlmock) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return (&conn{sqlmock: c}).QueryContext(ctx, query, args)
+}
+
+// Implement the "ExecerContext" interface
+func (c *sqlmock) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return (&conn{sqlmock: c}).ExecContext(ctx, query, args)
+}
+
+// Implement the "ConnBeginTx" interface
+func (c *sqlmock) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return (&conn{sqlmock: c}).BeginTx(ctx, opts)
+}
+
+// Implement the "ConnPrepareContext" interface
+func (c *sqlmock) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return (&conn{sqlmock: c}).PrepareContext(ctx, query)
+}
+
+// Implement the "Pinger" interface
+func (c *sqlmock) Ping(ctx context.Context) error {
+	return (&conn{sqlmock: c}).Ping(ctx)
+}
+
+// Query meets http://golang.org/pkg/database/sql/driver/#Queryer
+// Deprecated: Drivers should implement QueryerContext instead.
+func (c *sqlmock) Query(query string, args []driver.Value) (driver.Rows, error) {
+	return (&conn{sqlmock: c}).Query(query, args)
+}
+
+// Exec meets http://golang.org/pkg/database/sql/driver/#Execer
+// Deprecated: Drivers should implement ExecerContext instead.
+func (c *sqlmock) Exec(query string, args []driver.Value) (driver.Result, error) {
+	return (&conn{sqlmock: c}).Exec(query, args)
+}
@@ -0,0 +1,187 @@
+//go:build go1.9
+// +build go1.9
+
+package sqlmock
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMultipleConnectionsExpectedOnEachConnection(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New(MultipleConnectionsOption(true))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	first := mock.ExpectConnect()
+	first.ExpectExec("SET search_path").WillReturnResult(NewResult(0, 0))
+	second := mock.ExpectConnect()
+	first.ExpectQuery("SELECT name FROM users").WillReturnRows(NewRows([]string{"name"}).AddRow("john"))
+	second.ExpectQuery("SELECT name FROM users").WillReturnRows(NewRows([]string{"name"}).AddRow("jane"))
+
+	ctx := context.Background()
+	c1, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error on connect: %s", err)
+	}
+	defer c1.Close()
+	if _, err := c1.ExecContext(ctx, "SET search_path TO app"); err != nil {
+		t.Fatalf("unexpected error on exec: %s", err)
+	}
+
+	c2, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error on connect: %s", err)
+	}
+	defer c2.Close()
+
+	var name string
+	if err := c1.QueryRowContext(ctx, "SELECT name FROM users").Scan(&name); err != nil {
+		t.Fatalf("unexpected error on query: %s", err)
+	}
+	if err := c2.QueryRowContext(ctx, "SELECT name FROM users").Scan(&name); err != nil {
+		t.Fatalf("unexpected error on query: %s", err)
+	}
+	if name != "jane" {
+		t.Errorf("expected the second connection to return jane, but got %q", name)
+	}
+
+	if first.ID() != 1 || second.ID() != 2 {
+		t.Errorf("expected connections 1 and 2, but got %d and %d", first.ID(), second.ID())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	calls := mock.Calls()
+	if len(calls) != 5 {
+		t.Fatalf("expected 5 calls to be recorded, but got %d: %v", len(calls), calls)
+	}
+	for i, conn := range []int{1, 1, 2, 1, 2} {
+		if calls[i].Conn != conn {
+			t.Errorf("expected call %s to run on connection %d, but got %d", calls[i], conn, calls[i].Conn)
+		}
+	}
+}
+
+func TestMultipleConnectionsQueryOnWrongConnection(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New(MultipleConnectionsOption(true))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	first := mock.ExpectConnect()
+	mock.ExpectConnect()
+	first.ExpectExec("SET search_path").WillReturnResult(NewResult(0, 0))
+
+	ctx := context.Background()
+	c1, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error on connect: %s", err)
+	}
+	defer c1.Close()
+	c2, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error on connect: %s", err)
+	}
+	defer c2.Close()
+
+	_, err = c2.ExecContext(ctx, "SET search_path TO app")
+	if err == nil {
+		t.Fatal("expected an error, since exec ran on the second connection")
+	}
+	if !strings.Contains(err.Error(), "but it ran on connection 2") {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestMultipleConnectionsUnexpectedConnect(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New(MultipleConnectionsOption(true))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectConnect()
+
+	ctx := context.Background()
+	c1, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error on connect: %s", err)
+	}
+	defer c1.Close()
+	if _, err := db.Conn(ctx); err == nil {
+		t.Fatal("expected an error, since only one connection was expected")
+	}
+
+	if calls := mock.UnexpectedCalls(); len(calls) != 1 || calls[0].Kind != CallConnect {
+		t.Errorf("expected the second connect to be rejected, but got: %v", calls)
+	}
+}
+
+func TestConnectRetriedOnBadConnection(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New(MultipleConnectionsOption(true))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectConnect().WillReturnError(driver.ErrBadConn).Times(2)
+	mock.ExpectConnect()
+	mock.ExpectQuery("SELECT name FROM users").WillReturnRows(NewRows([]string{"name"}).AddRow("john"))
+
+	var name string
+	if err := db.QueryRow("SELECT name FROM users").Scan(&name); err != nil {
+		t.Fatalf("unexpected error on query: %s", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestConnectError(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New(MultipleConnectionsOption(true))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectConnect().WillReturnError(errors.New("connection refused"))
+
+	if err := db.Ping(); err == nil || err.Error() != "connection refused" {
+		t.Errorf("expected connection to be refused, but got: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestExpectConnectWithoutMultipleConnections(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	cn := mock.ExpectConnect().WillReturnError(errors.New("connection refused")).WillDelayFor(0)
+	cn.ExpectExec("UPDATE users").WillReturnResult(NewResult(0, 1))
+
+	if _, err := db.Exec("UPDATE users SET name = 'jane'"); err != nil {
+		t.Errorf("expected the exec to run on the single connection, but got: %s", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected the connection not to be expected, but got: %s", err)
+	}
+}
@@ -0,0 +1,180 @@
+package sqlmock
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQueryInTransactionOfExpectedBegin(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	begin := mock.ExpectBegin()
+	begin.ExpectQuery("SELECT name FROM users").WillReturnRows(NewRows([]string{"name"}).AddRow("john"))
+	begin.ExpectExec("UPDATE users").WillReturnResult(NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+	var name string
+	if err := tx.QueryRow("SELECT name FROM users").Scan(&name); err != nil {
+		t.Fatalf("unexpected error on query: %s", err)
+	}
+	if _, err := tx.Exec("UPDATE users SET name = 'jane'"); err != nil {
+		t.Fatalf("unexpected error on exec: %s", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("unexpected error on commit: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestQueryOutsideOfExpectedTransaction(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin().ExpectExec("UPDATE users").WillReturnResult(NewResult(0, 1))
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+	defer tx.Rollback()
+
+	_, err = db.Exec("UPDATE users SET name = 'jane'")
+	if err == nil {
+		t.Fatal("expected an error, since exec did not run in the transaction")
+	}
+	if !strings.Contains(err.Error(), "expected to run in the transaction started by ExpectedBegin") {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestQueryAfterCommitOfExpectedTransaction(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	begin := mock.ExpectBegin()
+	mock.ExpectCommit()
+	begin.ExpectQuery("SELECT name FROM users").WillReturnRows(NewRows([]string{"name"}).AddRow("john"))
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("unexpected error on commit: %s", err)
+	}
+
+	_, err = db.Query("SELECT name FROM users")
+	if err == nil {
+		t.Fatal("expected an error, since the transaction was already committed")
+	}
+	if !strings.Contains(err.Error(), "the transaction was already committed") {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestQueryInTxAndOutsideTx(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT count").OutsideTx().WillReturnRows(NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectExec("DELETE FROM users").InTx().WillReturnResult(NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM posts").InTx().WillReturnResult(NewResult(0, 1))
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+	defer tx.Rollback()
+
+	// the transaction holds its connection, so the query runs on another one
+	var count int
+	if err := db.QueryRow("SELECT count(*) FROM users").Scan(&count); err != nil {
+		t.Fatalf("unexpected error on query: %s", err)
+	}
+	if _, err := tx.Exec("DELETE FROM users"); err != nil {
+		t.Fatalf("unexpected error on exec: %s", err)
+	}
+
+	_, err = db.Exec("DELETE FROM posts")
+	if err == nil {
+		t.Fatal("expected an error, since exec did not run in a transaction")
+	}
+	if !strings.Contains(err.Error(), "expected to run in a transaction, but it ran outside of any") {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestUnorderedQueriesMatchTheirTransaction(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.MatchExpectationsInOrder(false)
+
+	first := mock.ExpectBegin()
+	second := mock.ExpectBegin()
+	first.ExpectQuery("SELECT name").WillReturnRows(NewRows([]string{"name"}).AddRow("first"))
+	second.ExpectQuery("SELECT name").WillReturnRows(NewRows([]string{"name"}).AddRow("second"))
+	mock.ExpectCommit().Times(2)
+
+	tx1, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+	tx2, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+
+	var name string
+	if err := tx2.QueryRow("SELECT name").Scan(&name); err != nil {
+		t.Fatalf("unexpected error on query: %s", err)
+	}
+	if name != "second" {
+		t.Errorf("expected query to match the second transaction, but got %q", name)
+	}
+	if err := tx1.QueryRow("SELECT name").Scan(&name); err != nil {
+		t.Fatalf("unexpected error on query: %s", err)
+	}
+	if name != "first" {
+		t.Errorf("expected query to match the first transaction, but got %q", name)
+	}
+
+	if err := tx1.Commit(); err != nil {
+		t.Fatalf("unexpected error on commit: %s", err)
+	}
+	if err := tx2.Commit(); err != nil {
+		t.Fatalf("unexpected error on commit: %s", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
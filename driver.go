@@ -1,6 +1,7 @@
 package sqlmock
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
@@ -24,15 +25,32 @@ type mockDriver struct {
 
 func (d *mockDriver) Open(dsn string) (driver.Conn, error) {
 	d.Lock()
-	defer d.Unlock()
-
 	c, ok := d.conns[dsn]
+	d.Unlock()
 	if !ok {
 		return c, fmt.Errorf("expected a connection to be available, but it is not")
 	}
 
+	return c.newConn(context.Background())
+}
+
+// newConn opens a new connection to the mock database
+func (c *sqlmock) newConn(ctx context.Context) (driver.Conn, error) {
+	c.drv.Lock()
 	c.opened++
-	return c, nil
+	c.connections++
+	cn := &conn{sqlmock: c, id: c.connections}
+	c.drv.Unlock()
+
+	if c.multipleConns {
+		if err := cn.dial(ctx); err != nil {
+			c.drv.Lock()
+			c.opened--
+			c.drv.Unlock()
+			return nil, err
+		}
+	}
+	return cn, nil
 }
 
 // New creates sqlmock database connection and a mock to manage expectations.
@@ -40,15 +58,12 @@ func (d *mockDriver) Open(dsn string) (driver.Conn, error) {
 // a specific driver.
 // Pings db so that all expectations could be
 // asserted.
+//
+// Since Go 1.10 the database is opened with the mock as its driver.Connector,
+// so it is not registered by any DSN. Use NewWithDSN for the libraries, which
+// need a driver name and a DSN to open the database.
 func New(options ...SqlMockOption) (*sql.DB, Sqlmock, error) {
-	pool.Lock()
-	dsn := fmt.Sprintf("sqlmock_db_%d", pool.counter)
-	pool.counter++
-
-	smock := &sqlmock{dsn: dsn, drv: pool, ordered: true}
-	pool.conns[dsn] = smock
-	pool.Unlock()
-
+	smock := &sqlmock{drv: pool, ordered: true}
 	return smock.open(options)
 }
 
@@ -0,0 +1,22 @@
+//go:build !go1.10
+// +build !go1.10
+
+package sqlmock
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// openDB opens the database of the mock, registering
+// it by a generated DSN unless it was created with one
+func (c *sqlmock) openDB() (*sql.DB, error) {
+	if c.dsn == "" {
+		c.drv.Lock()
+		c.dsn = fmt.Sprintf("sqlmock_db_%d", c.drv.counter)
+		c.drv.counter++
+		c.drv.conns[c.dsn] = c
+		c.drv.Unlock()
+	}
+	return sql.Open("sqlmock", c.dsn)
+}
@@ -0,0 +1,49 @@
+//go:build go1.10
+// +build go1.10
+
+package sqlmock
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+// connector opens the connections to a mock database. The mock itself is
+// not the connector, since database/sql would call its Close method on
+// database Close, as if it was a connection.
+type connector struct {
+	mock *sqlmock
+}
+
+// Connect meets https://golang.org/pkg/database/sql/driver/#Connector
+func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.mock.newConn(ctx)
+}
+
+// Driver meets https://golang.org/pkg/database/sql/driver/#Connector
+func (c connector) Driver() driver.Driver {
+	return c.mock.drv
+}
+
+// OpenConnector meets https://golang.org/pkg/database/sql/driver/#DriverContext
+func (d *mockDriver) OpenConnector(dsn string) (driver.Connector, error) {
+	d.Lock()
+	defer d.Unlock()
+
+	c, ok := d.conns[dsn]
+	if !ok {
+		return nil, fmt.Errorf("expected a connection to be available, but it is not")
+	}
+	return connector{c}, nil
+}
+
+// openDB opens the database of the mock, unless the mock was
+// created with a DSN, it is opened directly with a connector
+func (c *sqlmock) openDB() (*sql.DB, error) {
+	if c.dsn == "" {
+		return sql.OpenDB(connector{c}), nil
+	}
+	return sql.Open("sqlmock", c.dsn)
+}
@@ -0,0 +1,81 @@
+//go:build go1.10
+// +build go1.10
+
+package sqlmock
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestNewIsNotRegisteredByDSN(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	smock := mock.(*sqlmock)
+	if smock.dsn != "" {
+		t.Errorf("expected mock not to be registered, but it is by dsn %s", smock.dsn)
+	}
+	if _, ok := db.Driver().(*mockDriver); !ok {
+		t.Errorf("expected database driver to be the mock driver, but got %T", db.Driver())
+	}
+
+	mock.ExpectQuery("SELECT").WillReturnRows(NewRows([]string{"id"}).AddRow(1))
+	var id int
+	if err := db.QueryRow("SELECT id FROM users").Scan(&id); err != nil {
+		t.Fatalf("unexpected error on query: %s", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestOpenRegisteredDriverByDSN(t *testing.T) {
+	t.Parallel()
+	_, mock, err := NewWithDSN("sqlmock_db_connector")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	// a library opening the database by the driver name and DSN
+	db, err := sql.Open("sqlmock", "sqlmock_db_connector")
+	if err != nil {
+		t.Fatalf("unexpected error on open: %s", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("DELETE FROM users").WillReturnResult(NewResult(0, 1))
+	if _, err := db.Exec("DELETE FROM users"); err != nil {
+		t.Fatalf("unexpected error on exec: %s", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	if _, err := sql.Open("sqlmock", "sqlmock_db_unknown"); err == nil {
+		t.Error("expected an error, since no mock is registered by the dsn")
+	}
+}
+
+func TestConnectDelayCancelled(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New(MultipleConnectionsOption(true))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectConnect().WillDelayFor(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := db.PingContext(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected the startup probe to time out, but got: %v", err)
+	}
+}
@@ -33,10 +33,6 @@ func TestShouldOpenConnectionIssue15(t *testing.T) {
 	if err != nil {
 		t.Errorf("expected no error, but got: %s", err)
 	}
-	if len(pool.conns) != 1 {
-		t.Errorf("expected 1 connection in pool, but there is: %d", len(pool.conns))
-	}
-
 	smock, _ := mock.(*sqlmock)
 	if smock.opened != 1 {
 		t.Errorf("expected 1 connection on mock to be opened, but there is: %d", smock.opened)
@@ -86,10 +82,6 @@ func TestTwoOpenConnectionsOnTheSameDSN(t *testing.T) {
 	if err != nil {
 		t.Errorf("expected no error, but got: %s", err)
 	}
-	if len(pool.conns) != 2 {
-		t.Errorf("expected 2 connection in pool, but there is: %d", len(pool.conns))
-	}
-
 	if db == db2 {
 		t.Errorf("expected not the same database instance, but it is the same")
 	}
@@ -126,7 +118,10 @@ func TestNewDSN(t *testing.T) {
 }
 
 func TestDuplicateNewDSN(t *testing.T) {
-	if _, _, err := NewWithDSN("sqlmock_db_1"); err == nil {
+	if _, _, err := NewWithDSN("sqlmock_db_duplicate"); err != nil {
+		t.Fatalf("expected no error on NewWithDSN, but got: %s", err)
+	}
+	if _, _, err := NewWithDSN("sqlmock_db_duplicate"); err == nil {
 		t.Error("expected error on NewWithDSN")
 	}
 }
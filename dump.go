@@ -0,0 +1,240 @@
+//go:build go1.8
+// +build go1.8
+
+package sqlmock
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UpdateEnv is the environment variable, which makes MatchGoldenGo write
+// the golden file instead of comparing it, when it is set to a non empty
+// value, as in SQLMOCK_UPDATE=1 go test ./...
+const UpdateEnv = "SQLMOCK_UPDATE"
+
+// DumpAsGo writes the calls received by the mock, including the
+// unexpected ones, as Go code expecting them in the same order:
+//
+//	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name FROM users WHERE id = ?")).
+//		WithArgs(1).
+//		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+//			AddRow(1, "john"))
+//
+// The rows, results and errors of the expected calls are the ones
+// returned, the unexpected calls return no rows and an empty result.
+// The rows and results computed by WillRespond are not known, they
+// are left empty with a comment to be filled in.
+// Time arguments are matched by ArgTimeWithin and the arguments of
+// types without a Go literal are matched by AnyArg.
+func (c *sqlmock) DumpAsGo(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for i, call := range c.Calls() {
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		bw.WriteString(goExpectation(call))
+	}
+	return bw.Flush()
+}
+
+// MatchGoldenGo compares the calls received by the mock, dumped as by
+// DumpAsGo, with the golden file at path and returns an error at the
+// first line which differs. If UpdateEnv is set, it writes the golden
+// file instead, creating its directory if it does not exist.
+func (c *sqlmock) MatchGoldenGo(path string) error {
+	var buf bytes.Buffer
+	if err := c.DumpAsGo(&buf); err != nil {
+		return err
+	}
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(path, buf.Bytes(), 0644)
+	}
+
+	golden, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading golden file: %s, run with %s=1 to create it", err, UpdateEnv)
+	}
+	if bytes.Equal(golden, buf.Bytes()) {
+		return nil
+	}
+
+	expected := strings.Split(string(golden), "\n")
+	actual := strings.Split(buf.String(), "\n")
+	line := 0
+	for line < len(expected) && line < len(actual) && expected[line] == actual[line] {
+		line++
+	}
+	return fmt.Errorf("calls do not match the golden file %s at line %d, run with %s=1 to update it:\n  expected: %s\n  actual:   %s",
+		path, line+1, UpdateEnv, goldenLine(expected, line), goldenLine(actual, line))
+}
+
+func goldenLine(lines []string, i int) string {
+	if i >= len(lines) {
+		return "<end of file>"
+	}
+	return lines[i]
+}
+
+// goExpectation returns the Go code expecting the call
+func goExpectation(call Call) string {
+	var code []string
+	switch call.Kind {
+	case CallQuery:
+		code = append(code, fmt.Sprintf("mock.ExpectQuery(regexp.QuoteMeta(%s))", goString(call.SQL)))
+	case CallExec:
+		code = append(code, fmt.Sprintf("mock.ExpectExec(regexp.QuoteMeta(%s))", goString(call.SQL)))
+	case CallPrepare:
+		code = append(code, fmt.Sprintf("mock.ExpectPrepare(regexp.QuoteMeta(%s))", goString(call.SQL)))
+	default:
+		code = append(code, fmt.Sprintf("mock.Expect%s()", call.Kind))
+	}
+
+	if len(call.Args) > 0 {
+		args := make([]string, len(call.Args))
+		for i, arg := range call.Args {
+			args[i] = goArg(arg.Value)
+			if arg.Name != "" {
+				args[i] = fmt.Sprintf("sql.Named(%q, %s)", arg.Name, args[i])
+			}
+		}
+		code = append(code, "WithArgs("+strings.Join(args, ", ")+")")
+	}
+
+	// the error of an unexpected call is the one rejecting it
+	if call.Expectation != nil && call.Err != nil {
+		code = append(code, "WillReturnError("+goError(call.Err)+")")
+		return strings.Join(code, ".\n\t") + "\n"
+	}
+
+	switch call.Kind {
+	case CallQuery:
+		rows := "sqlmock.NewRows(nil)"
+		if e, ok := call.Expectation.(*ExpectedQuery); ok {
+			if e.responder != nil {
+				rows += " /* rows computed by WillRespond */"
+			} else if sets := goRowSets(e.rows); sets != "" {
+				rows = sets
+			}
+		}
+		code = append(code, "WillReturnRows("+rows+")")
+	case CallExec:
+		result := "sqlmock.NewResult(0, 0)"
+		if e, ok := call.Expectation.(*ExpectedExec); ok {
+			if e.responder != nil {
+				result += " /* result computed by WillRespond */"
+			} else if e.result != nil {
+				id, _ := e.result.LastInsertId()
+				affected, _ := e.result.RowsAffected()
+				result = fmt.Sprintf("sqlmock.NewResult(%d, %d)", id, affected)
+			}
+		}
+		code = append(code, "WillReturnResult("+result+")")
+	}
+	return strings.Join(code, ".\n\t") + "\n"
+}
+
+// goRowSets returns the Go code creating the row sets of a query,
+// or an empty string if the rows are not known
+func goRowSets(rows driver.Rows) string {
+	var rs *rowSets
+	switch r := rows.(type) {
+	case *rowSets:
+		rs = r
+	case *rowSetsWithDefinition:
+		rs = r.rowSets
+	default:
+		return ""
+	}
+
+	sets := make([]string, len(rs.sets))
+	for i, set := range rs.sets {
+		cols := make([]string, len(set.cols))
+		for j, col := range set.cols {
+			cols[j] = strconv.Quote(col)
+		}
+		code := fmt.Sprintf("sqlmock.NewRows([]string{%s})", strings.Join(cols, ", "))
+		for _, row := range set.rows {
+			values := make([]string, len(row))
+			for j, v := range row {
+				values[j] = goValue(v)
+			}
+			code += ".\n\t\tAddRow(" + strings.Join(values, ", ") + ")"
+		}
+		sets[i] = code
+	}
+	return strings.Join(sets, ", ")
+}
+
+// goArg returns the Go code of an argument, times are matched
+// regardless of their location and the values without a Go
+// literal by any argument
+func goArg(v driver.Value) string {
+	switch value := v.(type) {
+	case time.Time:
+		return fmt.Sprintf("sqlmock.ArgTimeWithin(%s, 0)", goValue(value))
+	case nil, int64, bool, string, []byte:
+		return goValue(v)
+	case float64:
+		if !math.IsNaN(value) && !math.IsInf(value, 0) {
+			return goValue(v)
+		}
+	}
+	return fmt.Sprintf("sqlmock.AnyArg() /* %T */", v)
+}
+
+// goValue returns the Go literal of a value
+func goValue(v driver.Value) string {
+	switch value := v.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return goString(value)
+	case []byte:
+		return fmt.Sprintf("[]byte(%s)", goString(string(value)))
+	case float64:
+		s := strconv.FormatFloat(value, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".eIN") {
+			s += ".0" // not to be an int64
+		}
+		return s
+	case time.Time:
+		t := value.UTC()
+		return fmt.Sprintf("time.Date(%d, time.%s, %d, %d, %d, %d, %d, time.UTC)",
+			t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond())
+	}
+	return fmt.Sprintf("%#v", v)
+}
+
+// goString returns the string literal, raw if it spans lines or has quotes
+func goString(s string) string {
+	if strings.ContainsAny(s, "\n\"") && !strings.ContainsAny(s, "`\r") && strconv.CanBackquote(strings.Replace(s, "\n", "", -1)) {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+// goError returns the Go code of the error returned by a call
+func goError(err error) string {
+	switch {
+	case errors.Is(err, driver.ErrBadConn):
+		return "driver.ErrBadConn"
+	case errors.Is(err, ErrCancelled):
+		return "sqlmock.ErrCancelled"
+	}
+	return fmt.Sprintf("errors.New(%s)", goString(err.Error()))
+}
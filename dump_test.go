@@ -0,0 +1,180 @@
+//go:build go1.8
+// +build go1.8
+
+package sqlmock
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dumpedCalls makes the calls dumped by the tests
+func dumpedCalls(t *testing.T) Sqlmock {
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM users").
+		WillReturnRows(NewRows([]string{"id", "name", "score"}).AddRow(1, "john", 2.0).AddRow(2, nil, 0.5))
+	mock.ExpectExec("UPDATE users").WillReturnResult(NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM users").WillReturnError(errors.New("constraint failed"))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+	rows, err := tx.Query("SELECT id, name, score FROM users WHERE name = ?", "john")
+	if err != nil {
+		t.Fatalf("unexpected error on query: %s", err)
+	}
+	rows.Close()
+	if _, err := tx.Exec("UPDATE users SET active = ?", true); err != nil {
+		t.Fatalf("unexpected error on exec: %s", err)
+	}
+	tx.Exec("DELETE FROM users WHERE id = ?", 1)
+	created := time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC)
+	tx.Exec("INSERT INTO \"audit\" (at, data, who)\nVALUES (?, ?, :who)", created, []byte("x"), sql.Named("who", "me"))
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("unexpected error on commit: %s", err)
+	}
+	return mock
+}
+
+func TestDumpAsGo(t *testing.T) {
+	t.Parallel()
+	mock := dumpedCalls(t)
+
+	var buf bytes.Buffer
+	if err := mock.DumpAsGo(&buf); err != nil {
+		t.Fatalf("unexpected error dumping the calls: %s", err)
+	}
+	golden, err := ioutil.ReadFile(filepath.Join("testdata", "dump.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != string(golden) {
+		t.Errorf("expected the dump:\n%s\nbut got:\n%s", golden, buf.String())
+	}
+}
+
+func TestDumpAsGoResponders(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillRespond(func(ctx context.Context, query string, args []driver.NamedValue) (*Rows, error) {
+		return NewRows([]string{"id"}).AddRow(args[0].Value), nil
+	})
+	mock.ExpectExec("DELETE").WillRespond(func(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+		return NewResult(0, 3), nil
+	})
+
+	var id int
+	if err := db.QueryRow("SELECT id FROM users WHERE id = ?", 7).Scan(&id); err != nil {
+		t.Fatalf("unexpected error on query: %s", err)
+	}
+	if _, err := db.Exec("DELETE FROM users"); err != nil {
+		t.Fatalf("unexpected error on exec: %s", err)
+	}
+
+	var buf bytes.Buffer
+	if err := mock.DumpAsGo(&buf); err != nil {
+		t.Fatalf("unexpected error dumping the calls: %s", err)
+	}
+	expected := `mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM users WHERE id = ?")).
+	WithArgs(7).
+	WillReturnRows(sqlmock.NewRows(nil) /* rows computed by WillRespond */)
+
+mock.ExpectExec(regexp.QuoteMeta("DELETE FROM users")).
+	WillReturnResult(sqlmock.NewResult(0, 0) /* result computed by WillRespond */)
+`
+	if buf.String() != expected {
+		t.Errorf("expected the dump:\n%s\nbut got:\n%s", expected, buf.String())
+	}
+}
+
+func TestDumpAsGoValues(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		value    interface{}
+		expected string
+	}{
+		{int64(-3), "-3"},
+		{2.0, "2.0"},
+		{1e21, "1e+21"},
+		{"it's", `"it's"`},
+		{`say "hi"`, "`say \"hi\"`"},
+		{[]byte{0}, `[]byte("\x00")`},
+		{nil, "nil"},
+		{time.Date(2020, 1, 2, 3, 4, 5, 6, time.FixedZone("", 3600)), "time.Date(2020, time.January, 2, 2, 4, 5, 6, time.UTC)"},
+	}
+	for i, c := range cases {
+		if s := goValue(c.value); s != c.expected {
+			t.Errorf("expected value %s at %d case, but got %s", c.expected, i, s)
+		}
+	}
+
+	if s := goArg(struct{}{}); s != "sqlmock.AnyArg() /* struct {} */" {
+		t.Errorf("expected an argument without a Go literal to be any argument, but got %s", s)
+	}
+}
+
+func TestMatchGoldenGo(t *testing.T) {
+	t.Parallel()
+	mock := dumpedCalls(t)
+
+	if err := mock.MatchGoldenGo(filepath.Join("testdata", "dump.golden")); err != nil {
+		t.Errorf("expected the calls to match the golden file, but got: %s", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "changed.golden")
+	changed := "mock.ExpectBegin()\n\nmock.ExpectRollback()\n"
+	if err := ioutil.WriteFile(path, []byte(changed), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := mock.MatchGoldenGo(path)
+	if err == nil {
+		t.Fatal("expected an error, since the golden file is different")
+	}
+	if exp := "at line 3, run with SQLMOCK_UPDATE=1 to update it:\n  expected: mock.ExpectRollback()\n  actual:   mock.ExpectQuery("; !strings.Contains(err.Error(), exp) {
+		t.Errorf("expected the error to show the first different line, but got: %s", err)
+	}
+
+	err = mock.MatchGoldenGo(filepath.Join(t.TempDir(), "missing.golden"))
+	if err == nil || !strings.Contains(err.Error(), "run with SQLMOCK_UPDATE=1 to create it") {
+		t.Errorf("expected an error, since the golden file does not exist, but got: %v", err)
+	}
+}
+
+func TestMatchGoldenGoUpdates(t *testing.T) {
+	// not parallel, since it sets the environment
+	mock := dumpedCalls(t)
+	path := filepath.Join(t.TempDir(), "golden", "calls.golden")
+
+	os.Setenv(UpdateEnv, "1")
+	err := mock.MatchGoldenGo(path)
+	os.Unsetenv(UpdateEnv)
+	if err != nil {
+		t.Fatalf("unexpected error writing the golden file: %s", err)
+	}
+
+	if err := mock.MatchGoldenGo(path); err != nil {
+		t.Errorf("expected the calls to match the written golden file, but got: %s", err)
+	}
+}
@@ -0,0 +1,68 @@
+package sqlmock
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExpectationsError is returned by ExpectationsWereMet and lists every
+// problem found with the expectations, so that all of them could be fixed
+// at once. It may be retrieved from the returned error using errors.As.
+type ExpectationsError struct {
+	// Unmet lists expectations, which were not triggered
+	// as many times as expected
+	Unmet []string
+	// UnclosedStatements lists expected prepared statements,
+	// which were expected to be closed, but were not
+	UnclosedStatements []string
+	// UnclosedRows lists expected queries, which rows were
+	// expected to be closed, but were not
+	UnclosedRows []string
+	// Unexpected lists errors returned to the calls, which did
+	// not match any expectation and were rejected
+	Unexpected []error
+
+	problems []string
+}
+
+func (e *ExpectationsError) unmet(ex expectation, calls string) {
+	e.Unmet = append(e.Unmet, ex.String())
+	if calls != "" {
+		e.problems = append(e.problems, fmt.Sprintf("there is a remaining expectation which was not matched, %s: %s", calls, ex))
+		return
+	}
+	e.problems = append(e.problems, fmt.Sprintf("there is a remaining expectation which was not matched: %s", ex))
+}
+
+func (e *ExpectationsError) unclosedStatement(prep *ExpectedPrepare) {
+	e.UnclosedStatements = append(e.UnclosedStatements, prep.String())
+	e.problems = append(e.problems, fmt.Sprintf("expected prepared statement to be closed, but it was not: %s", prep))
+}
+
+func (e *ExpectationsError) unclosedRows(query *ExpectedQuery) {
+	e.UnclosedRows = append(e.UnclosedRows, query.String())
+	e.problems = append(e.problems, fmt.Sprintf("expected query rows to be closed, but it was not: %s", query))
+}
+
+func (e *ExpectationsError) unexpected(err error) {
+	e.Unexpected = append(e.Unexpected, err)
+	e.problems = append(e.problems, fmt.Sprintf("unexpected call was rejected: %s", err))
+}
+
+func (e *ExpectationsError) empty() bool {
+	return len(e.problems) == 0
+}
+
+// Error returns a human readable report of all the problems found,
+// a single problem is reported on its own.
+func (e *ExpectationsError) Error() string {
+	if len(e.problems) == 1 {
+		return e.problems[0]
+	}
+
+	msg := fmt.Sprintf("there are %d problems with expectations:", len(e.problems))
+	for i, problem := range e.problems {
+		msg += fmt.Sprintf("\n  %d) %s", i+1, strings.Replace(problem, "\n", "\n     ", -1))
+	}
+	return msg
+}
@@ -0,0 +1,161 @@
+package sqlmock
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestExpectationsErrorListsAllProblems(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectPrepare("SELECT").WillBeClosed()
+	mock.ExpectQuery("SELECT").WillReturnRows(NewRows([]string{"id"}).AddRow(1)).RowsWillBeClosed()
+	mock.ExpectExec("INSERT INTO users").WillReturnResult(NewResult(1, 1))
+	mock.ExpectExec("UPDATE users").WillReturnResult(NewResult(0, 1))
+	mock.MatchExpectationsInOrder(false)
+
+	if _, err := db.Prepare("SELECT"); err != nil {
+		t.Fatalf("unexpected error on prepare: %s", err)
+	}
+	if _, err := db.Query("SELECT"); err != nil {
+		t.Fatalf("unexpected error on query: %s", err)
+	}
+	// the error is swallowed, but must be reported anyway
+	_, _ = db.Exec("DELETE FROM users")
+
+	err = mock.ExpectationsWereMet()
+	var report *ExpectationsError
+	if !errors.As(err, &report) {
+		t.Fatalf("expected an *ExpectationsError, but got: %T - %v", err, err)
+	}
+
+	if len(report.Unmet) != 2 {
+		t.Errorf("expected 2 unmet expectations, but got %d", len(report.Unmet))
+	}
+	if len(report.UnclosedStatements) != 1 {
+		t.Errorf("expected 1 unclosed statement, but got %d", len(report.UnclosedStatements))
+	}
+	if len(report.UnclosedRows) != 1 {
+		t.Errorf("expected 1 query with unclosed rows, but got %d", len(report.UnclosedRows))
+	}
+	if len(report.Unexpected) != 1 {
+		t.Errorf("expected 1 unexpected call, but got %d", len(report.Unexpected))
+	}
+
+	msg := err.Error()
+	if !strings.HasPrefix(msg, "there are 5 problems with expectations:") {
+		t.Errorf("unexpected error message: %s", msg)
+	}
+	if !strings.Contains(msg, "unexpected call was rejected: call to ExecQuery 'DELETE FROM users'") {
+		t.Errorf("expected the rejected call to be reported, but got: %s", msg)
+	}
+}
+
+func TestExpectationsErrorSingleProblem(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+
+	err = mock.ExpectationsWereMet()
+	if err == nil {
+		t.Fatal("expected an error, since begin was not called")
+	}
+	expected := "there is a remaining expectation which was not matched: ExpectedBegin => expecting database transaction Begin"
+	if err.Error() != expected {
+		t.Errorf("expected error %q, but got %q", expected, err.Error())
+	}
+}
+
+func TestExpectationsWereMetWithoutProblems(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected no error, but got: %v", err)
+	}
+}
+
+func TestReportUnexpectedCallsOptionDisabled(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New(ReportUnexpectedCallsOption(false))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("DELETE FROM users"); err == nil {
+		t.Fatal("expected an error, since exec was not expected")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected unexpected calls not to be reported, but got: %s", err)
+	}
+}
+
+func TestConcurrentQueriesClosingIdleConnections(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	// more concurrent queries than the 2 idle connections kept by
+	// database/sql, so that it closes the others once they are done
+	const queries = 5
+	mock.MatchExpectationsInOrder(false)
+	for i := 0; i < queries; i++ {
+		mock.ExpectQuery("SELECT").WillDelayFor(50 * time.Millisecond).WillReturnRows(NewRows([]string{"id"}).AddRow(i))
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < queries; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var id int
+			if err := db.QueryRow("SELECT id FROM users").Scan(&id); err != nil {
+				t.Errorf("unexpected error on query: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("expected the idle connections closed by database/sql not to be reported, but got: %s", err)
+	}
+}
+
+func TestUnexpectedCloseReportedWhenClosesAreExpected(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectExec("UPDATE").WillReturnResult(NewResult(0, 1))
+	mock.ExpectClose()
+	if err := db.Close(); err == nil {
+		t.Error("expected an error, since the close was expected after the exec")
+	}
+	if err := mock.ExpectationsWereMet(); err == nil || !strings.Contains(err.Error(), "Close") {
+		t.Errorf("expected the unexpected close to be reported, but got: %v", err)
+	}
+}
@@ -12,21 +12,105 @@ import (
 // an expectation interface
 type expectation interface {
 	fulfilled() bool
+	exhausted() bool
+	pass()
+	calls() string
 	Lock()
 	Unlock()
 	String() string
 }
 
+// unlimited is the upper bound of calls for expectations
+// which may be triggered any number of times
+const unlimited = -1
+
 // common expectation struct
 // satisfies the expectation interface
 type commonExpectation struct {
 	sync.Mutex
-	triggered bool
-	err       error
+	triggered  int // number of times the expectation was matched
+	minCalls   int
+	maxCalls   int
+	repeatable bool // whether minCalls and maxCalls were set
+	passed     bool // whether a following expectation was triggered in order
+	err        error
+}
+
+// bounds returns the number of calls expected, by default
+// an expectation must be triggered exactly once
+func (e *commonExpectation) bounds() (min, max int) {
+	if !e.repeatable {
+		return 1, 1
+	}
+	return e.minCalls, e.maxCalls
 }
 
+// fulfilled reports whether the expectation was triggered
+// at least as many times as it is required to
 func (e *commonExpectation) fulfilled() bool {
-	return e.triggered
+	min, _ := e.bounds()
+	return e.triggered >= min
+}
+
+// exhausted reports whether the expectation cannot be
+// triggered anymore
+func (e *commonExpectation) exhausted() bool {
+	_, max := e.bounds()
+	return e.passed || max != unlimited && e.triggered >= max
+}
+
+// pass closes the expectation, since a following one was triggered
+func (e *commonExpectation) pass() {
+	e.passed = true
+}
+
+func (e *commonExpectation) setCalls(min, max int) {
+	if min < 0 || (max != unlimited && max < min) {
+		panic(fmt.Sprintf("invalid number of expected calls: min %d, max %d", min, max))
+	}
+	e.minCalls, e.maxCalls = min, max
+	e.repeatable = true
+}
+
+func (e *commonExpectation) times(n int) {
+	e.setCalls(n, n)
+}
+
+func (e *commonExpectation) atLeast(n int) {
+	_, max := e.bounds()
+	if !e.repeatable || (max != unlimited && max < n) {
+		max = unlimited
+	}
+	e.setCalls(n, max)
+}
+
+func (e *commonExpectation) atMost(n int) {
+	min, _ := e.bounds()
+	if !e.repeatable || min > n {
+		min = 0
+	}
+	e.setCalls(min, n)
+}
+
+// calls describes how many times a repeatable expectation was
+// triggered compared to how many times it is expected to be
+func (e *commonExpectation) calls() string {
+	if !e.repeatable {
+		return ""
+	}
+	min, max := e.minCalls, e.maxCalls
+	var expected string
+	switch {
+	case min == max:
+		expected = fmt.Sprintf("exactly %d", min)
+	case max == unlimited:
+		expected = fmt.Sprintf("at least %d", min)
+	case min == 0:
+		expected = fmt.Sprintf("at most %d", max)
+	default:
+		expected = fmt.Sprintf("between %d and %d", min, max)
+	}
+	return fmt.Sprintf("called %d times, expected %s", e.triggered, expected)
 }
 
 // ExpectedClose is used to manage *sql.DB.Close expectation
@@ -50,12 +134,87 @@ func (e *ExpectedClose) String() string {
 	return msg
 }
 
+// ExpectedConnection is used to manage a new connection opened by
+// database/sql. Returned by *Sqlmock.ExpectConnect.
+type ExpectedConnection struct {
+	commonExpectation
+	mock     *sqlmock
+	id       int
+	delay    time.Duration
+	detached bool // not expected, since connections are not distinct
+}
+
+// WillReturnError allows to set an error for opening the connection.
+// database/sql retries the operation, which needed the connection,
+// when driver.ErrBadConn is returned.
+func (e *ExpectedConnection) WillReturnError(err error) *ExpectedConnection {
+	e.err = err
+	return e
+}
+
+// WillDelayFor allows to specify duration for which it will delay
+// opening the connection. May be used together with Context
+func (e *ExpectedConnection) WillDelayFor(duration time.Duration) *ExpectedConnection {
+	e.delay = duration
+	return e
+}
+
+// ID returns the ID of the connection opened for this expectation,
+// 0 if it was not opened yet.
+func (e *ExpectedConnection) ID() int {
+	e.Lock()
+	defer e.Unlock()
+	return e.id
+}
+
+// ExpectQuery expects Query() or QueryRow() to be called
+// with expectedSQL query on this connection.
+func (e *ExpectedConnection) ExpectQuery(expectedSQL string) *ExpectedQuery {
+	eq := e.mock.ExpectQuery(expectedSQL)
+	if !e.detached {
+		eq.connection = e
+	}
+	return eq
+}
+
+// ExpectExec expects Exec() to be called with
+// expectedSQL query on this connection.
+func (e *ExpectedConnection) ExpectExec(expectedSQL string) *ExpectedExec {
+	ee := e.mock.ExpectExec(expectedSQL)
+	if !e.detached {
+		ee.connection = e
+	}
+	return ee
+}
+
+// ExpectPrepare expects Prepare() to be called with
+// expectedSQL query on this connection.
+func (e *ExpectedConnection) ExpectPrepare(expectedSQL string) *ExpectedPrepare {
+	ep := e.mock.ExpectPrepare(expectedSQL)
+	if !e.detached {
+		ep.connection = e
+	}
+	return ep
+}
+
+// String returns string representation
+func (e *ExpectedConnection) String() string {
+	msg := "ExpectedConnection => expecting database Connect"
+	if e.err != nil {
+		msg += fmt.Sprintf(", which should return error: %s", e.err)
+	}
+	return msg
+}
+
 // ExpectedBegin is used to manage *sql.DB.Begin expectation
 // returned by *Sqlmock.ExpectBegin.
 type ExpectedBegin struct {
 	commonExpectation
-	delay  time.Duration
-	txOpts *driver.TxOptions
+	mock      *sqlmock
+	delay     time.Duration
+	isolation *driver.IsolationLevel
+	readOnly  *bool
+	optsMatch func(driver.TxOptions) error
 }
 
 // WillReturnError allows to set an error for *sql.DB.Begin action
@@ -67,8 +226,14 @@ func (e *ExpectedBegin) WillReturnError(err error) *ExpectedBegin {
 // String returns string representation
 func (e *ExpectedBegin) String() string {
 	msg := "ExpectedBegin => expecting database transaction Begin"
-	if e.txOpts != nil {
-		msg += fmt.Sprintf(", with tx options: %+v", e.txOpts)
+	if e.isolation != nil {
+		msg += fmt.Sprintf(", with isolation level: %s", sql.IsolationLevel(*e.isolation))
+	}
+	if e.readOnly != nil {
+		msg += fmt.Sprintf(", with read only: %t", *e.readOnly)
+	}
+	if e.optsMatch != nil {
+		msg += ", with tx options matched by a function"
 	}
 	if e.err != nil {
 		msg += fmt.Sprintf(", which should return error: %s", e.err)
@@ -76,6 +241,12 @@ func (e *ExpectedBegin) String() string {
 	return msg
 }
 
+// WillReturnBadConn makes the Begin return driver.ErrBadConn
+func (e *ExpectedBegin) WillReturnBadConn() *ExpectedBegin {
+	e.err = driver.ErrBadConn
+	return e
+}
+
 // WillDelayFor allows to specify duration for which it will delay
 // result. May be used together with Context
 func (e *ExpectedBegin) WillDelayFor(duration time.Duration) *ExpectedBegin {
@@ -83,15 +254,132 @@ func (e *ExpectedBegin) WillDelayFor(duration time.Duration) *ExpectedBegin {
 	return e
 }
 
-// WithTxOptions allows to set transaction options for *sql.DB.Begin action
+// WithTxOptions allows to set transaction options for *sql.DB.Begin action,
+// both the isolation level and the read only flag must match.
 func (e *ExpectedBegin) WithTxOptions(opts sql.TxOptions) *ExpectedBegin {
-	e.txOpts = &driver.TxOptions{
-		Isolation: driver.IsolationLevel(opts.Isolation),
-		ReadOnly:  opts.ReadOnly,
+	return e.WithIsolation(opts.Isolation).WithReadOnly(opts.ReadOnly)
+}
+
+// WithIsolation expects the transaction to begin with the given
+// isolation level, whatever its other options are.
+func (e *ExpectedBegin) WithIsolation(level sql.IsolationLevel) *ExpectedBegin {
+	isolation := driver.IsolationLevel(level)
+	e.isolation = &isolation
+	return e
+}
+
+// WithReadOnly expects the transaction to begin read only or not,
+// whatever its other options are.
+func (e *ExpectedBegin) WithReadOnly(readOnly bool) *ExpectedBegin {
+	e.readOnly = &readOnly
+	return e
+}
+
+// WithTxOptionsMatcher allows to match the transaction options with a
+// function, which returns an error describing why they do not match.
+func (e *ExpectedBegin) WithTxOptionsMatcher(match func(opts driver.TxOptions) error) *ExpectedBegin {
+	e.optsMatch = match
+	return e
+}
+
+// matchTxOptions returns an error naming the first
+// transaction option, which does not match
+func (e *ExpectedBegin) matchTxOptions(opts driver.TxOptions) error {
+	if e.isolation != nil && *e.isolation != opts.Isolation {
+		return fmt.Errorf("expected isolation level %s, but got %s", sql.IsolationLevel(*e.isolation), sql.IsolationLevel(opts.Isolation))
 	}
+	if e.readOnly != nil && *e.readOnly != opts.ReadOnly {
+		return fmt.Errorf("expected read only to be %t, but got %t", *e.readOnly, opts.ReadOnly)
+	}
+	if e.optsMatch != nil {
+		return e.optsMatch(opts)
+	}
+	return nil
+}
+
+// ExpectQuery expects Query() or QueryRow() to be called with expectedSQL
+// query in the transaction started by this Begin. It fails if the query
+// runs outside of the transaction, in another one, or once the transaction
+// was committed or rolled back.
+func (e *ExpectedBegin) ExpectQuery(expectedSQL string) *ExpectedQuery {
+	eq := e.mock.ExpectQuery(expectedSQL)
+	eq.begin = e
+	return eq
+}
+
+// ExpectExec expects Exec() to be called with expectedSQL query
+// in the transaction started by this Begin.
+func (e *ExpectedBegin) ExpectExec(expectedSQL string) *ExpectedExec {
+	ee := e.mock.ExpectExec(expectedSQL)
+	ee.begin = e
+	return ee
+}
+
+// ExpectPrepare expects Prepare() to be called with expectedSQL query
+// in the transaction started by this Begin. The statement queries and
+// execs expected on the *ExpectedPrepare must run in the same transaction.
+func (e *ExpectedBegin) ExpectPrepare(expectedSQL string) *ExpectedPrepare {
+	ep := e.mock.ExpectPrepare(expectedSQL)
+	ep.begin = e
+	return ep
+}
+
+// ExpectSavepoint expects a savepoint to be created
+// in the transaction started by this Begin.
+func (e *ExpectedBegin) ExpectSavepoint(name string) *ExpectedSavepoint {
+	es := e.mock.ExpectSavepoint(name)
+	es.begin = e
+	return es
+}
+
+// ExpectRollbackToSavepoint expects the transaction started
+// by this Begin to be rolled back to a savepoint.
+func (e *ExpectedBegin) ExpectRollbackToSavepoint(name string) *ExpectedSavepoint {
+	es := e.mock.ExpectRollbackToSavepoint(name)
+	es.begin = e
+	return es
+}
+
+// ExpectReleaseSavepoint expects a savepoint to be released
+// in the transaction started by this Begin.
+func (e *ExpectedBegin) ExpectReleaseSavepoint(name string) *ExpectedSavepoint {
+	es := e.mock.ExpectReleaseSavepoint(name)
+	es.begin = e
+	return es
+}
+
+// ExpectedSavepoint is used to manage the savepoint statements executed with
+// *sql.Tx.Exec, like SAVEPOINT, ROLLBACK TO SAVEPOINT or RELEASE SAVEPOINT.
+// Returned by *Sqlmock.ExpectSavepoint, *Sqlmock.ExpectRollbackToSavepoint
+// and *Sqlmock.ExpectReleaseSavepoint.
+type ExpectedSavepoint struct {
+	commonExpectation
+	scope
+	savepointStmt
+}
+
+// WillReturnError allows to set an error for the savepoint statement
+func (e *ExpectedSavepoint) WillReturnError(err error) *ExpectedSavepoint {
+	e.err = err
 	return e
 }
 
+func (e *ExpectedSavepoint) matches(stmt savepointStmt) bool {
+	return e.action == stmt.action && strings.EqualFold(e.name, stmt.name)
+}
+
+// String returns string representation
+func (e *ExpectedSavepoint) String() string {
+	msg := "ExpectedSavepoint => expecting " + e.savepointStmt.String()
+	if s := e.scope.String(); s != "" {
+		msg += ", which " + strings.Replace(s, "\n  - ", " and ", -1)
+	}
+	if e.err != nil {
+		msg += fmt.Sprintf(", which should return error: %s", e.err)
+	}
+	return msg
+}
+
 // ExpectedCommit is used to manage *sql.Tx.Commit expectation
 // returned by *Sqlmock.ExpectCommit.
 type ExpectedCommit struct {
@@ -140,6 +428,7 @@ func (e *ExpectedRollback) String() string {
 type ExpectedQuery struct {
 	queryBasedExpectation
 	rows             driver.Rows
+	responder        queryResponder
 	delay            time.Duration
 	rowsMustBeClosed bool
 	rowsWereClosed   bool
@@ -169,6 +458,18 @@ func (e *ExpectedQuery) WithoutArgs() *ExpectedQuery {
 	return e
 }
 
+// InTx expects this query to run in a transaction.
+func (e *ExpectedQuery) InTx() *ExpectedQuery {
+	e.inTx, e.outsideTx = true, false
+	return e
+}
+
+// OutsideTx expects this query to run outside of any transaction.
+func (e *ExpectedQuery) OutsideTx() *ExpectedQuery {
+	e.inTx, e.outsideTx = false, true
+	return e
+}
+
 // RowsWillBeClosed expects this query rows to be closed.
 func (e *ExpectedQuery) RowsWillBeClosed() *ExpectedQuery {
 	e.rowsMustBeClosed = true
@@ -181,6 +482,12 @@ func (e *ExpectedQuery) WillReturnError(err error) *ExpectedQuery {
 	return e
 }
 
+// WillReturnBadConn makes the query return driver.ErrBadConn
+func (e *ExpectedQuery) WillReturnBadConn() *ExpectedQuery {
+	e.err = driver.ErrBadConn
+	return e
+}
+
 // WillDelayFor allows to specify duration for which it will delay
 // result. May be used together with Context
 func (e *ExpectedQuery) WillDelayFor(duration time.Duration) *ExpectedQuery {
@@ -198,15 +505,23 @@ func (e *ExpectedQuery) String() string {
 	} else {
 		msg += "\n  - is with arguments:\n"
 		for i, arg := range e.args {
-			msg += fmt.Sprintf("    %d - %+v\n", i, arg)
+			msg += fmt.Sprintf("    %d - %s\n", i, describeArg(arg))
 		}
 		msg = strings.TrimSpace(msg)
 	}
 
+	if s := e.scope.String(); s != "" {
+		msg += "\n  - " + s
+	}
+
 	if e.rows != nil {
 		msg += fmt.Sprintf("\n  - %s", e.rows)
 	}
 
+	if e.responder != nil {
+		msg += "\n  - should respond with rows computed by a responder"
+	}
+
 	if e.err != nil {
 		msg += fmt.Sprintf("\n  - should return error: %s", e.err)
 	}
@@ -218,8 +533,9 @@ func (e *ExpectedQuery) String() string {
 // Returned by *Sqlmock.ExpectExec.
 type ExpectedExec struct {
 	queryBasedExpectation
-	result driver.Result
-	delay  time.Duration
+	result    driver.Result
+	responder execResponder
+	delay     time.Duration
 }
 
 // WithArgs will match given expected args to actual database exec operation arguments.
@@ -246,12 +562,30 @@ func (e *ExpectedExec) WithoutArgs() *ExpectedExec {
 	return e
 }
 
+// InTx expects this exec to run in a transaction.
+func (e *ExpectedExec) InTx() *ExpectedExec {
+	e.inTx, e.outsideTx = true, false
+	return e
+}
+
+// OutsideTx expects this exec to run outside of any transaction.
+func (e *ExpectedExec) OutsideTx() *ExpectedExec {
+	e.inTx, e.outsideTx = false, true
+	return e
+}
+
 // WillReturnError allows to set an error for expected database exec action
 func (e *ExpectedExec) WillReturnError(err error) *ExpectedExec {
 	e.err = err
 	return e
 }
 
+// WillReturnBadConn makes the exec return driver.ErrBadConn
+func (e *ExpectedExec) WillReturnBadConn() *ExpectedExec {
+	e.err = driver.ErrBadConn
+	return e
+}
+
 // WillDelayFor allows to specify duration for which it will delay
 // result. May be used together with Context
 func (e *ExpectedExec) WillDelayFor(duration time.Duration) *ExpectedExec {
@@ -270,11 +604,15 @@ func (e *ExpectedExec) String() string {
 		msg += "\n  - is with arguments:\n"
 		var margs []string
 		for i, arg := range e.args {
-			margs = append(margs, fmt.Sprintf("    %d - %+v", i, arg))
+			margs = append(margs, fmt.Sprintf("    %d - %s", i, describeArg(arg)))
 		}
 		msg += strings.Join(margs, "\n")
 	}
 
+	if s := e.scope.String(); s != "" {
+		msg += "\n  - " + s
+	}
+
 	if e.result != nil {
 		if res, ok := e.result.(*result); ok {
 			msg += "\n  - should return Result having:"
@@ -286,6 +624,10 @@ func (e *ExpectedExec) String() string {
 		}
 	}
 
+	if e.responder != nil {
+		msg += "\n  - should respond with Result computed by a responder"
+	}
+
 	if e.err != nil {
 		msg += fmt.Sprintf("\n  - should return error: %s", e.err)
 	}
@@ -306,6 +648,7 @@ func (e *ExpectedExec) WillReturnResult(result driver.Result) *ExpectedExec {
 // Returned by *Sqlmock.ExpectPrepare.
 type ExpectedPrepare struct {
 	commonExpectation
+	scope
 	mock         *sqlmock
 	expectSQL    string
 	statement    driver.Stmt
@@ -321,6 +664,12 @@ func (e *ExpectedPrepare) WillReturnError(err error) *ExpectedPrepare {
 	return e
 }
 
+// WillReturnBadConn makes the Prepare return driver.ErrBadConn
+func (e *ExpectedPrepare) WillReturnBadConn() *ExpectedPrepare {
+	e.err = driver.ErrBadConn
+	return e
+}
+
 // WillReturnCloseError allows to set an error for this prepared statement Close action
 func (e *ExpectedPrepare) WillReturnCloseError(err error) *ExpectedPrepare {
 	e.closeErr = err
@@ -334,6 +683,18 @@ func (e *ExpectedPrepare) WillDelayFor(duration time.Duration) *ExpectedPrepare
 	return e
 }
 
+// InTx expects this statement to be prepared in a transaction.
+func (e *ExpectedPrepare) InTx() *ExpectedPrepare {
+	e.inTx, e.outsideTx = true, false
+	return e
+}
+
+// OutsideTx expects this statement to be prepared outside of any transaction.
+func (e *ExpectedPrepare) OutsideTx() *ExpectedPrepare {
+	e.inTx, e.outsideTx = false, true
+	return e
+}
+
 // WillBeClosed expects this prepared statement to
 // be closed.
 func (e *ExpectedPrepare) WillBeClosed() *ExpectedPrepare {
@@ -347,6 +708,7 @@ func (e *ExpectedPrepare) ExpectQuery() *ExpectedQuery {
 	eq := &ExpectedQuery{}
 	eq.expectSQL = e.expectSQL
 	eq.converter = e.mock.converter
+	eq.scope = e.scope
 	e.mock.expected = append(e.mock.expected, eq)
 	return eq
 }
@@ -357,6 +719,7 @@ func (e *ExpectedPrepare) ExpectExec() *ExpectedExec {
 	eq := &ExpectedExec{}
 	eq.expectSQL = e.expectSQL
 	eq.converter = e.mock.converter
+	eq.scope = e.scope
 	e.mock.expected = append(e.mock.expected, eq)
 	return eq
 }
@@ -366,6 +729,10 @@ func (e *ExpectedPrepare) String() string {
 	msg := "ExpectedPrepare => expecting Prepare statement which:"
 	msg += "\n  - matches sql: '" + e.expectSQL + "'"
 
+	if s := e.scope.String(); s != "" {
+		msg += "\n  - " + s
+	}
+
 	if e.err != nil {
 		msg += fmt.Sprintf("\n  - should return error: %s", e.err)
 	}
@@ -381,6 +748,7 @@ func (e *ExpectedPrepare) String() string {
 // adds a query matching logic
 type queryBasedExpectation struct {
 	commonExpectation
+	scope
 	expectSQL string
 	converter driver.ValueConverter
 	args      []driver.Value
@@ -415,3 +783,31 @@ func (e *ExpectedPing) String() string {
 	}
 	return msg
 }
+
+// ExpectedResetSession is used to manage the session reset of a connection,
+// which database/sql does before reusing it. Returned by *Sqlmock.ExpectResetSession.
+type ExpectedResetSession struct {
+	commonExpectation
+}
+
+// WillReturnError allows to set an error for the session reset
+func (e *ExpectedResetSession) WillReturnError(err error) *ExpectedResetSession {
+	e.err = err
+	return e
+}
+
+// WillReturnBadConn makes the session reset return driver.ErrBadConn,
+// so that database/sql discards the connection and opens another one.
+func (e *ExpectedResetSession) WillReturnBadConn() *ExpectedResetSession {
+	e.err = driver.ErrBadConn
+	return e
+}
+
+// String returns string representation
+func (e *ExpectedResetSession) String() string {
+	msg := "ExpectedResetSession => expecting connection ResetSession"
+	if e.err != nil {
+		msg += fmt.Sprintf(", which should return error: %s", e.err)
+	}
+	return msg
+}
@@ -7,6 +7,13 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"reflect"
+	"strconv"
+)
+
+// responders are supported only on Go 1.8 and above
+type (
+	queryResponder func()
+	execResponder  func()
 )
 
 // WillReturnRows specifies the set of resulting rows that will be returned
@@ -16,6 +23,15 @@ func (e *ExpectedQuery) WillReturnRows(rows *Rows) *ExpectedQuery {
 	return e
 }
 
+// rowsForCall returns the rows to be returned by a single triggered
+// query, each call gets its own copy positioned before the first row
+func (e *ExpectedQuery) rowsForCall() driver.Rows {
+	if rs, ok := e.rows.(*rowSets); ok {
+		return rs.clone()
+	}
+	return e.rows
+}
+
 func (e *queryBasedExpectation) argsMatches(args []namedValue) error {
 	if nil == e.args {
 		if e.noArgs && len(args) > 0 {
@@ -31,8 +47,8 @@ func (e *queryBasedExpectation) argsMatches(args []namedValue) error {
 		matcher, ok := e.args[k].(Argument)
 		if ok {
 			// @TODO: does it make sense to pass value instead of named value?
-			if !matcher.Match(v.Value) {
-				return fmt.Errorf("matcher %T could not match %d argument %T - %+v", matcher, k, args[k], args[k])
+			if err := argMatches(strconv.Itoa(k), matcher, args[k], v.Value); err != nil {
+				return err
 			}
 			continue
 		}
@@ -55,6 +71,25 @@ func (e *queryBasedExpectation) argsMatches(args []namedValue) error {
 	return nil
 }
 
+// matches reports whether the query and arguments of an actual
+// call satisfy the expectation
+func (e *queryBasedExpectation) matches(matcher QueryMatcher, query string, args []namedValue) bool {
+	return matcher.Match(e.expectSQL, query) == nil && e.attemptArgMatch(args) == nil
+}
+
+// capture records the arguments of the triggered
+// expectation in the expected captures
+func (e *queryBasedExpectation) capture(args []namedValue) {
+	for k, v := range args {
+		if k >= len(e.args) {
+			break
+		}
+		if c, ok := e.args[k].(*Capture); ok {
+			c.record(v.Value)
+		}
+	}
+}
+
 func (e *queryBasedExpectation) attemptArgMatch(args []namedValue) (err error) {
 	// catch panic
 	defer func() {
@@ -4,15 +4,60 @@
 package sqlmock
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 )
 
+// computes the rows of a triggered query from the actual call
+type queryResponder func(ctx context.Context, query string, args []driver.NamedValue) (*Rows, error)
+
+// computes the result of a triggered exec from the actual call
+type execResponder func(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error)
+
 // WillReturnRows specifies the set of resulting rows that will be returned
 // by the triggered query
 func (e *ExpectedQuery) WillReturnRows(rows ...*Rows) *ExpectedQuery {
+	e.rows = e.rowSets(rows...)
+	return e
+}
+
+// WillRespond arranges for the triggered query to compute its rows from
+// the actual query and arguments, instead of returning the rows set
+// with WillReturnRows. The responder is called each time the expectation
+// is matched, after the WillDelayFor delay has passed, unless the context
+// was cancelled meanwhile. An error returned by the responder is returned
+// by the query.
+//
+// The responder may be called concurrently when the expectation is
+// repeatable and queries are run from goroutines.
+func (e *ExpectedQuery) WillRespond(responder func(ctx context.Context, query string, args []driver.NamedValue) (*Rows, error)) *ExpectedQuery {
+	e.responder = responder
+	return e
+}
+
+// WillRespond arranges for the triggered exec action to compute its result
+// from the actual query and arguments, instead of returning the result set
+// with WillReturnResult. The responder is called each time the expectation
+// is matched, after the WillDelayFor delay has passed, unless the context
+// was cancelled meanwhile. An error returned by the responder is returned
+// by the exec action.
+//
+// The responder may be called concurrently when the expectation is
+// repeatable and statements are executed from goroutines.
+func (e *ExpectedExec) WillRespond(responder func(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error)) *ExpectedExec {
+	e.responder = responder
+	return e
+}
+
+// rowSets wraps the given rows as driver.Rows, the column
+// metadata is available only if all of the sets define it
+func (e *ExpectedQuery) rowSets(rows ...*Rows) driver.Rows {
 	defs := 0
 	sets := make([]*Rows, len(rows))
 	for i, r := range rows {
@@ -22,11 +67,51 @@ func (e *ExpectedQuery) WillReturnRows(rows ...*Rows) *ExpectedQuery {
 		}
 	}
 	if defs > 0 && defs == len(sets) {
-		e.rows = &rowSetsWithDefinition{&rowSets{sets: sets, ex: e}}
-	} else {
-		e.rows = &rowSets{sets: sets, ex: e}
+		return &rowSetsWithDefinition{&rowSets{sets: sets, ex: e}}
 	}
-	return e
+	return &rowSets{sets: sets, ex: e}
+}
+
+// respond calls the responder of a triggered query
+func (e *ExpectedQuery) respond(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if ctx.Err() != nil {
+		return nil, ErrCancelled
+	}
+	rows, err := e.responder(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	if rows == nil {
+		return nil, fmt.Errorf("Query '%s' with args %+v, responder must return a database/sql/driver.Rows or an error", query, args)
+	}
+	return e.rowSets(rows), nil
+}
+
+// respond calls the responder of a triggered exec action
+func (e *ExpectedExec) respond(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if ctx.Err() != nil {
+		return nil, ErrCancelled
+	}
+	res, err := e.responder(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, fmt.Errorf("ExecQuery '%s' with args %+v, responder must return a database/sql/driver.Result or an error", query, args)
+	}
+	return res, nil
+}
+
+// rowsForCall returns the rows to be returned by a single triggered
+// query, each call gets its own copy positioned before the first row
+func (e *ExpectedQuery) rowsForCall() driver.Rows {
+	switch rs := e.rows.(type) {
+	case *rowSetsWithDefinition:
+		return &rowSetsWithDefinition{rs.clone()}
+	case *rowSets:
+		return rs.clone()
+	}
+	return e.rows
 }
 
 func (e *queryBasedExpectation) argsMatches(args []driver.NamedValue) error {
@@ -36,6 +121,9 @@ func (e *queryBasedExpectation) argsMatches(args []driver.NamedValue) error {
 		}
 		return nil
 	}
+	if named, ok := e.namedArgs(); ok {
+		return e.namedArgsMatches(named, args)
+	}
 	if len(args) != len(e.args) {
 		return fmt.Errorf("expected %d, but got %d arguments", len(e.args), len(args))
 	}
@@ -44,8 +132,8 @@ func (e *queryBasedExpectation) argsMatches(args []driver.NamedValue) error {
 		// custom argument matcher
 		matcher, ok := e.args[k].(Argument)
 		if ok {
-			if !matcher.Match(v.Value) {
-				return fmt.Errorf("matcher %T could not match %d argument %T - %+v", matcher, k, args[k], args[k])
+			if err := argMatches(strconv.Itoa(k), matcher, args[k], v.Value); err != nil {
+				return err
 			}
 			continue
 		}
@@ -73,6 +161,129 @@ func (e *queryBasedExpectation) argsMatches(args []driver.NamedValue) error {
 	return nil
 }
 
+// namedArgs returns the expected arguments by name,
+// if every expected argument is a sql.NamedArg
+func (e *queryBasedExpectation) namedArgs() (map[string]driver.Value, bool) {
+	named := make(map[string]driver.Value, len(e.args))
+	for _, arg := range e.args {
+		n, ok := arg.(sql.NamedArg)
+		if !ok {
+			return nil, false
+		}
+		named[n.Name] = n.Value
+	}
+	return named, true
+}
+
+// namedArgsMatches matches the actual arguments by name,
+// regardless of the order they were passed in
+func (e *queryBasedExpectation) namedArgsMatches(expected map[string]driver.Value, args []driver.NamedValue) error {
+	var extra []string
+	seen := make(map[string]bool, len(args))
+	for _, v := range args {
+		if v.Name == "" {
+			return fmt.Errorf("argument %d is not named, but named arguments were expected", v.Ordinal-1)
+		}
+		dval, ok := expected[v.Name]
+		if !ok {
+			extra = append(extra, v.Name)
+			continue
+		}
+		seen[v.Name] = true
+
+		pos := strconv.Quote(v.Name)
+		if matcher, ok := dval.(Argument); ok {
+			if err := argMatches(pos, matcher, v, v.Value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		darg, err := e.converter.ConvertValue(dval)
+		if err != nil {
+			return fmt.Errorf("could not convert %s argument %T - %+v to driver value: %s", pos, dval, dval, err)
+		}
+		if !reflect.DeepEqual(darg, v.Value) {
+			return fmt.Errorf("argument %s expected [%T - %+v] does not match actual [%T - %+v]", pos, darg, darg, v.Value, v.Value)
+		}
+	}
+
+	var missing []string
+	for name := range expected {
+		if !seen[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+
+	switch {
+	case len(missing) > 0 && len(extra) > 0:
+		return fmt.Errorf("missing named arguments: %s, unexpected named arguments: %s", strings.Join(missing, ", "), strings.Join(extra, ", "))
+	case len(missing) > 0:
+		return fmt.Errorf("missing named arguments: %s", strings.Join(missing, ", "))
+	case len(extra) > 0:
+		return fmt.Errorf("unexpected named arguments: %s", strings.Join(extra, ", "))
+	}
+	return nil
+}
+
+// capture records the arguments of the triggered
+// expectation in the expected captures
+func (e *queryBasedExpectation) capture(args []driver.NamedValue) {
+	named, byName := e.namedArgs()
+	for k, v := range args {
+		var arg driver.Value
+		if byName {
+			arg = named[v.Name]
+		} else if k < len(e.args) {
+			arg = e.args[k]
+		}
+		if c, ok := arg.(*Capture); ok {
+			c.record(v.Value)
+		}
+	}
+}
+
+// sortedNamedArgs returns the arguments of the map
+// as sql.NamedArg values, sorted by name
+func sortedNamedArgs(args map[string]driver.Value) []driver.Value {
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	named := make([]driver.Value, len(names))
+	for i, name := range names {
+		named[i] = sql.Named(name, args[name])
+	}
+	return named
+}
+
+// WithNamedArgs will match given expected args to actual database query
+// arguments by name, regardless of the order they are passed in. A value
+// may be an sqlmock.Argument to match the argument in specific way.
+// The same applies to WithArgs, when every expected argument is a sql.NamedArg.
+// Must not be used together with WithoutArgs()
+func (e *ExpectedQuery) WithNamedArgs(args map[string]driver.Value) *ExpectedQuery {
+	return e.WithArgs(sortedNamedArgs(args)...)
+}
+
+// WithNamedArgs will match given expected args to actual database exec
+// operation arguments by name, regardless of the order they are passed in.
+// A value may be an sqlmock.Argument to match the argument in specific way.
+// The same applies to WithArgs, when every expected argument is a sql.NamedArg.
+// Must not be used together with WithoutArgs()
+func (e *ExpectedExec) WithNamedArgs(args map[string]driver.Value) *ExpectedExec {
+	return e.WithArgs(sortedNamedArgs(args)...)
+}
+
+// matches reports whether the query and arguments of an actual
+// call satisfy the expectation
+func (e *queryBasedExpectation) matches(matcher QueryMatcher, query string, args []driver.NamedValue) bool {
+	return matcher.Match(e.expectSQL, query) == nil && e.attemptArgMatch(args) == nil
+}
+
 func (e *queryBasedExpectation) attemptArgMatch(args []driver.NamedValue) (err error) {
 	// catch panic
 	defer func() {
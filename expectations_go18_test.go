@@ -183,3 +183,88 @@ func Test_queryBasedExpectation_attemptArgMatch(t *testing.T) {
 		t.Errorf("error expected")
 	}
 }
+
+func TestQueryExpectationArgumentMatchers(t *testing.T) {
+	e := &queryBasedExpectation{converter: driver.DefaultParameterConverter}
+	e.args = []driver.Value{ArgEq(5), ArgRegexp("^jo"), ArgOr(ArgNil(), ArgBetween(1.0, 2.0))}
+
+	against := []driver.NamedValue{
+		{Value: int64(5), Ordinal: 1},
+		{Value: "john", Ordinal: 2},
+		{Value: nil, Ordinal: 3},
+	}
+	if err := e.argsMatches(against); err != nil {
+		t.Errorf("arguments should match, but got: %s", err)
+	}
+
+	against[2].Value = float64(2.5)
+	if err := e.argsMatches(against); err == nil {
+		t.Error("arguments should not match, since the last argument is out of range")
+	}
+}
+
+func TestQueryExpectationNamedArgsInAnyOrder(t *testing.T) {
+	e := &queryBasedExpectation{converter: driver.DefaultParameterConverter}
+	e.args = []driver.Value{sql.Named("b", 2), sql.Named("a", ArgBetween(0, 5))}
+
+	against := []driver.NamedValue{
+		{Value: int64(1), Name: "a", Ordinal: 1},
+		{Value: int64(2), Name: "b", Ordinal: 2},
+	}
+	if err := e.argsMatches(against); err != nil {
+		t.Errorf("arguments should match regardless of the order, but got: %s", err)
+	}
+
+	cases := []struct {
+		against  []driver.NamedValue
+		expected string
+	}{
+		{
+			[]driver.NamedValue{{Value: int64(2), Name: "b", Ordinal: 1}},
+			"missing named arguments: a",
+		},
+		{
+			[]driver.NamedValue{
+				{Value: int64(2), Name: "b", Ordinal: 1},
+				{Value: int64(1), Name: "c", Ordinal: 2},
+			},
+			"missing named arguments: a, unexpected named arguments: c",
+		},
+		{
+			[]driver.NamedValue{
+				{Value: int64(1), Name: "a", Ordinal: 1},
+				{Value: int64(2), Name: "b", Ordinal: 2},
+				{Value: int64(3), Name: "c", Ordinal: 3},
+			},
+			"unexpected named arguments: c",
+		},
+		{
+			[]driver.NamedValue{
+				{Value: int64(1), Name: "a", Ordinal: 1},
+				{Value: int64(3), Name: "b", Ordinal: 2},
+			},
+			`argument "b" expected [int64 - 2] does not match actual [int64 - 3]`,
+		},
+		{
+			[]driver.NamedValue{
+				{Value: int64(7), Name: "a", Ordinal: 1},
+				{Value: int64(2), Name: "b", Ordinal: 2},
+			},
+			`argument "a" expected between 0 and 5 does not match actual [int64 - 7]: it is greater than 5`,
+		},
+		{
+			[]driver.NamedValue{
+				{Value: int64(2), Name: "b", Ordinal: 1},
+				{Value: int64(1), Ordinal: 2},
+			},
+			"argument 1 is not named, but named arguments were expected",
+		},
+	}
+
+	for i, c := range cases {
+		err := e.argsMatches(c.against)
+		if err == nil || err.Error() != c.expected {
+			t.Errorf("case %d: expected error %q, but got: %v", i, c.expected, err)
+		}
+	}
+}
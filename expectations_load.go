@@ -0,0 +1,382 @@
+package sqlmock
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+)
+
+// script is a sequence of expectations described in JSON
+type script struct {
+	Ordered      *bool        `json:"ordered,omitempty"`
+	Expectations []scriptStep `json:"expectations"`
+}
+
+// scriptStep is an expectation described in a script
+type scriptStep struct {
+	Type        string            `json:"type"`
+	SQL         string            `json:"sql,omitempty"`
+	Args        []json.RawMessage `json:"args,omitempty"`
+	NoArgs      bool              `json:"no_args,omitempty"`
+	Columns     []string          `json:"columns,omitempty"`
+	ColumnTypes []string          `json:"column_types,omitempty"`
+	ScanTypes   []string          `json:"scan_types,omitempty"`
+	Rows        json.RawMessage   `json:"rows,omitempty"`
+	Result      *scriptResult     `json:"result,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	Delay       string            `json:"delay,omitempty"`
+	Times       int               `json:"times,omitempty"`
+	Statements  []scriptStep      `json:"statements,omitempty"`
+}
+
+type scriptResult struct {
+	LastInsertID int64 `json:"last_insert_id"`
+	RowsAffected int64 `json:"rows_affected"`
+}
+
+// LoadExpectations adds the expectations described by a JSON script,
+// as if they were expected in order with the fluent API:
+//
+//	{
+//	  "ordered": true,
+//	  "expectations": [
+//	    {"type": "begin"},
+//	    {"type": "query", "sql": "SELECT (.+) FROM users WHERE id = ?", "args": [1],
+//	     "columns": ["id", "name"], "rows": [[1, "john"]]},
+//	    {"type": "exec", "sql": "UPDATE users", "args": [{"any": true}, 1],
+//	     "result": {"rows_affected": 1}},
+//	    {"type": "prepare", "sql": "DELETE FROM users", "statements": [
+//	      {"type": "exec", "args": [1], "error": "constraint failed"}
+//	    ]},
+//	    {"type": "commit"}
+//	  ]
+//	}
+//
+// The types are begin, commit, rollback, prepare, query, exec, ping
+// and close. The statements of a prepare are the queries and execs of
+// the prepared statement. An argument may be {"any": true} or
+// {"regexp": "expr"} to match it by an Argument, {"time": "RFC 3339"}
+// to match a time.Time at the same instant or {"bytes": "base64"} to
+// match a []byte. The rows are read by Rows.FromJSON, except that a
+// value may be {"bytes": "base64"} for a []byte, which a JSON string
+// cannot hold if it is not valid UTF-8. They are typed by the optional
+// column_types, the database type names, and scan_types, one of int64,
+// float64, bool, string, bytes or time for each column. The
+// error is returned as the expected error, delay is a time.Duration
+// string and times sets how many times the expectation is triggered.
+//
+// The whole script is validated before any expectation is added.
+func (c *sqlmock) LoadExpectations(rd io.Reader) error {
+	dec := json.NewDecoder(rd)
+	dec.DisallowUnknownFields()
+
+	var s script
+	if err := dec.Decode(&s); err != nil {
+		return fmt.Errorf("reading expectations: %s", err)
+	}
+
+	var build []func(*ExpectedPrepare)
+	for i, step := range s.Expectations {
+		fn, err := c.scriptExpectation(step, false)
+		if err != nil {
+			return fmt.Errorf("expectation %d (%s): %s", i+1, step.Type, err)
+		}
+		build = append(build, fn)
+	}
+
+	if s.Ordered != nil {
+		c.MatchExpectationsInOrder(*s.Ordered)
+	}
+	for _, fn := range build {
+		fn(nil)
+	}
+	return nil
+}
+
+// scriptExpectation validates the step and returns the function adding
+// the expectation, the statements of a prepare are expected on it
+func (c *sqlmock) scriptExpectation(step scriptStep, inPrepare bool) (func(*ExpectedPrepare), error) {
+	var err error
+	if step.Error != "" {
+		err = errors.New(step.Error)
+	}
+	var delay time.Duration
+	if step.Delay != "" {
+		d, perr := time.ParseDuration(step.Delay)
+		if perr != nil {
+			return nil, fmt.Errorf("invalid delay: %s", perr)
+		}
+		delay = d
+	}
+	if step.Times < 0 {
+		return nil, fmt.Errorf("invalid times: %d", step.Times)
+	}
+	if inPrepare && step.Type != "query" && step.Type != "exec" {
+		return nil, fmt.Errorf("a prepared statement may only expect query or exec")
+	}
+	if inPrepare && step.SQL != "" {
+		return nil, fmt.Errorf("a prepared statement runs the sql of the prepare")
+	}
+	if !inPrepare && step.SQL == "" && (step.Type == "query" || step.Type == "exec" || step.Type == "prepare") {
+		return nil, fmt.Errorf("sql is required")
+	}
+	if step.Type != "prepare" && len(step.Statements) > 0 {
+		return nil, fmt.Errorf("only a prepare may have statements")
+	}
+	if step.Type != "query" && (step.Rows != nil || step.Columns != nil || step.ColumnTypes != nil || step.ScanTypes != nil) {
+		return nil, fmt.Errorf("only a query may return rows")
+	}
+	if step.Type != "exec" && step.Result != nil {
+		return nil, fmt.Errorf("only an exec may return a result")
+	}
+	times := func(e interface{ times(int) }) {
+		if step.Times > 0 {
+			e.times(step.Times)
+		}
+	}
+
+	switch step.Type {
+	case "begin":
+		return func(*ExpectedPrepare) {
+			times(c.ExpectBegin().WillReturnError(err).WillDelayFor(delay))
+		}, nil
+	case "commit":
+		return func(*ExpectedPrepare) {
+			times(c.ExpectCommit().WillReturnError(err))
+		}, nil
+	case "rollback":
+		return func(*ExpectedPrepare) {
+			times(c.ExpectRollback().WillReturnError(err))
+		}, nil
+	case "ping":
+		if !c.monitorPings {
+			return nil, fmt.Errorf("pings are not monitored, use MonitorPingsOption to expect them")
+		}
+		return func(*ExpectedPrepare) {
+			times(c.ExpectPing().WillReturnError(err).WillDelayFor(delay))
+		}, nil
+	case "close":
+		return func(*ExpectedPrepare) {
+			times(c.ExpectClose().WillReturnError(err))
+		}, nil
+	case "prepare":
+		var statements []func(*ExpectedPrepare)
+		for i, st := range step.Statements {
+			fn, serr := c.scriptExpectation(st, true)
+			if serr != nil {
+				return nil, fmt.Errorf("statement %d (%s): %s", i+1, st.Type, serr)
+			}
+			statements = append(statements, fn)
+		}
+		return func(*ExpectedPrepare) {
+			e := c.ExpectPrepare(step.SQL).WillReturnError(err).WillDelayFor(delay)
+			times(e)
+			for _, fn := range statements {
+				fn(e)
+			}
+		}, nil
+	case "query":
+		args, aerr := scriptArgs(step)
+		if aerr != nil {
+			return nil, aerr
+		}
+		rows, rerr := c.scriptRows(step)
+		if rerr != nil {
+			return nil, rerr
+		}
+		return func(prepare *ExpectedPrepare) {
+			var e *ExpectedQuery
+			if prepare != nil {
+				e = prepare.ExpectQuery()
+			} else {
+				e = c.ExpectQuery(step.SQL)
+			}
+			switch {
+			case step.NoArgs:
+				e.WithoutArgs()
+			case args != nil:
+				e.WithArgs(args...)
+			}
+			if rows != nil {
+				e.WillReturnRows(rows)
+			}
+			times(e.WillReturnError(err).WillDelayFor(delay))
+		}, nil
+	case "exec":
+		args, aerr := scriptArgs(step)
+		if aerr != nil {
+			return nil, aerr
+		}
+		return func(prepare *ExpectedPrepare) {
+			var e *ExpectedExec
+			if prepare != nil {
+				e = prepare.ExpectExec()
+			} else {
+				e = c.ExpectExec(step.SQL)
+			}
+			switch {
+			case step.NoArgs:
+				e.WithoutArgs()
+			case args != nil:
+				e.WithArgs(args...)
+			}
+			if step.Result != nil {
+				e.WillReturnResult(NewResult(step.Result.LastInsertID, step.Result.RowsAffected))
+			}
+			times(e.WillReturnError(err).WillDelayFor(delay))
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown expectation type %q", step.Type)
+}
+
+// scriptRows returns the rows of the query step, if any
+func (c *sqlmock) scriptRows(step scriptStep) (*Rows, error) {
+	if step.Rows == nil && step.Columns == nil {
+		return nil, nil
+	}
+	rows := c.NewRows(step.Columns)
+	if step.ColumnTypes != nil || step.ScanTypes != nil {
+		columns, err := scriptColumns(step)
+		if err != nil {
+			return nil, err
+		}
+		rows.setColumns(columns)
+	}
+	if step.Rows != nil {
+		if _, err := rows.FromJSON(bytes.NewReader(step.Rows)); err != nil {
+			return nil, fmt.Errorf("invalid rows: %s", err)
+		}
+		for _, row := range rows.rows {
+			for i, v := range row {
+				if b, ok := scriptBytes(v); ok {
+					row[i] = b
+				}
+			}
+		}
+	}
+	return rows, nil
+}
+
+// scriptBytes decodes a {"bytes": "base64"} value of the rows,
+// which FromJSON reads as the JSON of the object
+func scriptBytes(v driver.Value) ([]byte, bool) {
+	raw, ok := v.([]byte)
+	if !ok || len(raw) == 0 || raw[0] != '{' {
+		return nil, false
+	}
+	var value struct {
+		Bytes *[]byte `json:"bytes"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&value); err != nil || value.Bytes == nil {
+		return nil, false
+	}
+	return *value.Bytes, true
+}
+
+// scan type samples by their names in a script
+var scriptScanTypes = map[string]interface{}{
+	"":        nil,
+	"int64":   int64(0),
+	"float64": float64(0),
+	"bool":    false,
+	"string":  "",
+	"bytes":   []byte(nil),
+	"time":    time.Time{},
+}
+
+// scriptColumns returns the column definitions of the query step
+func scriptColumns(step scriptStep) ([]*Column, error) {
+	if step.ColumnTypes != nil && len(step.ColumnTypes) != len(step.Columns) {
+		return nil, fmt.Errorf("expected %d column types, but got %d", len(step.Columns), len(step.ColumnTypes))
+	}
+	if step.ScanTypes != nil && len(step.ScanTypes) != len(step.Columns) {
+		return nil, fmt.Errorf("expected %d scan types, but got %d", len(step.Columns), len(step.ScanTypes))
+	}
+
+	columns := make([]*Column, len(step.Columns))
+	for i, name := range step.Columns {
+		var dbType, scanType string
+		if step.ColumnTypes != nil {
+			dbType = step.ColumnTypes[i]
+		}
+		if step.ScanTypes != nil {
+			scanType = step.ScanTypes[i]
+		}
+		sample, ok := scriptScanTypes[scanType]
+		if !ok {
+			return nil, fmt.Errorf("column %q: unknown scan type %q", name, scanType)
+		}
+		columns[i] = NewColumn(name).OfType(dbType, sample)
+	}
+	return columns, nil
+}
+
+// scriptArgs returns the expected arguments of the step
+func scriptArgs(step scriptStep) ([]driver.Value, error) {
+	if step.NoArgs && len(step.Args) > 0 {
+		return nil, fmt.Errorf("args and no_args must not be used together")
+	}
+	if step.Args == nil {
+		return nil, nil
+	}
+
+	args := make([]driver.Value, len(step.Args))
+	for i, raw := range step.Args {
+		arg, err := scriptArg(raw)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %s", i, err)
+		}
+		args[i] = arg
+	}
+	return args, nil
+}
+
+// scriptArg returns the argument value or matcher
+func scriptArg(raw json.RawMessage) (driver.Value, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '{' {
+		var matcher struct {
+			Any    bool       `json:"any"`
+			Regexp *string    `json:"regexp"`
+			Time   *time.Time `json:"time"`
+			Bytes  *[]byte    `json:"bytes"`
+		}
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&matcher); err != nil {
+			return nil, fmt.Errorf("invalid argument matcher: %s", err)
+		}
+		set := 0
+		for _, ok := range []bool{matcher.Any, matcher.Regexp != nil, matcher.Time != nil, matcher.Bytes != nil} {
+			if ok {
+				set++
+			}
+		}
+		switch {
+		case set != 1:
+		case matcher.Any:
+			return AnyArg(), nil
+		case matcher.Regexp != nil:
+			if _, err := regexp.Compile(*matcher.Regexp); err != nil {
+				return nil, err
+			}
+			return ArgRegexp(*matcher.Regexp), nil
+		case matcher.Time != nil:
+			return ArgTimeWithin(*matcher.Time, 0), nil
+		case matcher.Bytes != nil:
+			return *matcher.Bytes, nil
+		}
+		return nil, fmt.Errorf("invalid argument matcher %s, expected one of any, regexp, time or bytes", raw)
+	}
+	if len(raw) > 0 && raw[0] == '[' {
+		return nil, fmt.Errorf("unsupported argument %s", raw)
+	}
+	return jsonValue(raw, nil, nil)
+}
@@ -0,0 +1,132 @@
+package sqlmock
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadExpectations(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New(QueryMatcherOption(QueryMatcherSQL))
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	f, err := os.Open("testdata/checkout.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer f.Close()
+
+	if err := mock.LoadExpectations(f); err != nil {
+		t.Fatalf("unexpected error while loading expectations: %s", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+
+	var id int
+	var balance float64
+	if err := tx.QueryRow("SELECT id, balance FROM accounts WHERE user_id = ?", 7).Scan(&id, &balance); err != nil {
+		t.Fatalf("unexpected error on query: %s", err)
+	}
+	if id != 1 || balance != 100.5 {
+		t.Errorf("expected account 1 with balance 100.5, but got %d with %f", id, balance)
+	}
+
+	stmt, err := tx.Prepare("INSERT INTO orders(account_id, item) VALUES (?, ?)")
+	if err != nil {
+		t.Fatalf("unexpected error on prepare: %s", err)
+	}
+	for _, item := range []string{"book", "pen"} {
+		res, err := stmt.Exec(1, item)
+		if err != nil {
+			t.Fatalf("unexpected error on insert: %s", err)
+		}
+		if lastID, _ := res.LastInsertId(); lastID != 10 {
+			t.Errorf("expected last insert id 10, but got %d", lastID)
+		}
+	}
+
+	_, err = tx.Exec("UPDATE accounts SET balance = ? WHERE id = ?", "90.5", 1)
+	if err == nil || err.Error() != "deadlock detected" {
+		t.Errorf("expected the scripted error, but got: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Errorf("unexpected error on rollback: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestLoadExpectationsInvalidScript(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		script   string
+		expected string
+	}{
+		{`[]`, "reading expectations: "},
+		{`{"expectations": [{"type": "begin", "sql_typo": "x"}]}`, `reading expectations: json: unknown field "sql_typo"`},
+		{`{"expectations": [{"type": "select"}]}`, `expectation 1 (select): unknown expectation type "select"`},
+		{`{"expectations": [{"type": "begin"}, {"type": "exec", "sql": "x", "rows": [[1]]}]}`, "expectation 2 (exec): only a query may return rows"},
+		{`{"expectations": [{"type": "query", "sql": "x", "result": {}}]}`, "expectation 1 (query): only an exec may return a result"},
+		{`{"expectations": [{"type": "ping", "delay": "soon"}]}`, "expectation 1 (ping): invalid delay: "},
+		{`{"expectations": [{"type": "exec", "sql": "x", "args": [{"regexp": "("}]}]}`, "expectation 1 (exec): argument 0: error parsing regexp: "},
+		{`{"expectations": [{"type": "exec", "sql": "x", "args": [{"any": true, "regexp": "x"}]}]}`, "expectation 1 (exec): argument 0: invalid argument matcher "},
+		{`{"expectations": [{"type": "exec", "sql": "x", "args": [1], "no_args": true}]}`, "expectation 1 (exec): args and no_args must not be used together"},
+		{`{"expectations": [{"type": "query", "sql": "x", "columns": ["id"], "rows": [[1, 2]]}]}`, "expectation 1 (query): invalid rows: row 1: expected 1 columns, but got 2"},
+		{`{"expectations": [{"type": "prepare", "sql": "x", "statements": [{"type": "commit"}]}]}`, "expectation 1 (prepare): statement 1 (commit): a prepared statement may only expect query or exec"},
+		{`{"expectations": [{"type": "prepare", "sql": "x", "statements": [{"type": "exec", "sql": "x"}]}]}`, "expectation 1 (prepare): statement 1 (exec): a prepared statement runs the sql of the prepare"},
+		{`{"expectations": [{"type": "commit", "times": -1}]}`, "expectation 1 (commit): invalid times: -1"},
+		{`{"expectations": [{"type": "begin"}, {"type": "ping"}]}`, "expectation 2 (ping): pings are not monitored, use MonitorPingsOption to expect them"},
+		{`{"expectations": [{"type": "query", "sql": "x", "columns": ["id"], "scan_types": ["uuid"]}]}`, `expectation 1 (query): column "id": unknown scan type "uuid"`},
+		{`{"expectations": [{"type": "query", "sql": "x", "columns": ["id"], "column_types": ["INT", "TEXT"]}]}`, "expectation 1 (query): expected 1 column types, but got 2"},
+		{`{"expectations": [{"type": "exec", "sql": "x", "column_types": ["INT"]}]}`, "expectation 1 (exec): only a query may return rows"},
+		{`{"expectations": [{"type": "exec", "sql": "x", "args": [{"time": "yesterday"}]}]}`, "expectation 1 (exec): argument 0: invalid argument matcher: "},
+		{`{"expectations": [{"type": "query", "no_args": true}]}`, "expectation 1 (query): sql is required"},
+		{`{"expectations": [{"type": "exec"}]}`, "expectation 1 (exec): sql is required"},
+		{`{"expectations": [{"type": "prepare", "statements": [{"type": "exec"}]}]}`, "expectation 1 (prepare): sql is required"},
+	}
+
+	for i, c := range cases {
+		db, mock, err := New()
+		if err != nil {
+			t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		err = mock.LoadExpectations(strings.NewReader(c.script))
+		if err == nil || !strings.HasPrefix(err.Error(), c.expected) {
+			t.Errorf("expected error %q at %d case, but got: %v", c.expected, i, err)
+		}
+		if len(mock.(*sqlmock).expected) != 0 {
+			t.Errorf("expected no expectations to be added at %d case", i)
+		}
+		db.Close()
+	}
+}
+
+func TestLoadExpectationsPing(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New(MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	script := `{"expectations": [{"type": "ping", "error": "connection refused"}]}`
+	if err := mock.LoadExpectations(strings.NewReader(script)); err != nil {
+		t.Fatalf("unexpected error loading the expectations: %s", err)
+	}
+	if err := db.Ping(); err == nil || err.Error() != "connection refused" {
+		t.Errorf("expected the ping to return the scripted error, but got: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,318 @@
+package sqlmock
+
+// By default every expectation must be triggered exactly once and is
+// skipped once it was matched. The methods below allow an expectation
+// to be matched a number of times, so the same statement executed in
+// a loop needs a single expectation.
+//
+// When expectations are matched in order, a repeatable expectation
+// which was already fulfilled does not block the next ones: the call
+// is matched against it first and falls through to the following
+// expectation if it does not match. Once a following expectation is
+// triggered, the ones before it cannot be matched anymore.
+
+// Times expects the database Close to be triggered exactly n times.
+func (e *ExpectedClose) Times(n int) *ExpectedClose {
+	e.times(n)
+	return e
+}
+
+// AtLeast expects the database Close to be triggered n times or more.
+// May be combined with AtMost to set both bounds.
+func (e *ExpectedClose) AtLeast(n int) *ExpectedClose {
+	e.atLeast(n)
+	return e
+}
+
+// AtMost expects the database Close to be triggered no more than n times.
+// May be combined with AtLeast to set both bounds.
+func (e *ExpectedClose) AtMost(n int) *ExpectedClose {
+	e.atMost(n)
+	return e
+}
+
+// AnyTimes allows the database Close to be triggered any number
+// of times, including none.
+func (e *ExpectedClose) AnyTimes() *ExpectedClose {
+	e.setCalls(0, unlimited)
+	return e
+}
+
+// Maybe allows the database Close to be triggered once or not at all.
+func (e *ExpectedClose) Maybe() *ExpectedClose {
+	e.setCalls(0, 1)
+	return e
+}
+
+// Times expects the database Connect to be triggered exactly n times.
+func (e *ExpectedConnection) Times(n int) *ExpectedConnection {
+	e.times(n)
+	return e
+}
+
+// AtLeast expects the database Connect to be triggered n times or more.
+// May be combined with AtMost to set both bounds.
+func (e *ExpectedConnection) AtLeast(n int) *ExpectedConnection {
+	e.atLeast(n)
+	return e
+}
+
+// AtMost expects the database Connect to be triggered no more than n times.
+// May be combined with AtLeast to set both bounds.
+func (e *ExpectedConnection) AtMost(n int) *ExpectedConnection {
+	e.atMost(n)
+	return e
+}
+
+// AnyTimes allows the database Connect to be triggered any number
+// of times, including none.
+func (e *ExpectedConnection) AnyTimes() *ExpectedConnection {
+	e.setCalls(0, unlimited)
+	return e
+}
+
+// Maybe allows the database Connect to be triggered once or not at all.
+func (e *ExpectedConnection) Maybe() *ExpectedConnection {
+	e.setCalls(0, 1)
+	return e
+}
+
+// Times expects the transaction Begin to be triggered exactly n times.
+func (e *ExpectedBegin) Times(n int) *ExpectedBegin {
+	e.times(n)
+	return e
+}
+
+// AtLeast expects the transaction Begin to be triggered n times or more.
+func (e *ExpectedBegin) AtLeast(n int) *ExpectedBegin {
+	e.atLeast(n)
+	return e
+}
+
+// AtMost expects the transaction Begin to be triggered no more than n times.
+func (e *ExpectedBegin) AtMost(n int) *ExpectedBegin {
+	e.atMost(n)
+	return e
+}
+
+// AnyTimes allows the transaction Begin to be triggered any number of times.
+func (e *ExpectedBegin) AnyTimes() *ExpectedBegin {
+	e.setCalls(0, unlimited)
+	return e
+}
+
+// Maybe allows the transaction Begin to be triggered once or not at all.
+func (e *ExpectedBegin) Maybe() *ExpectedBegin {
+	e.setCalls(0, 1)
+	return e
+}
+
+// Times expects the transaction Commit to be triggered exactly n times.
+func (e *ExpectedCommit) Times(n int) *ExpectedCommit {
+	e.times(n)
+	return e
+}
+
+// AtLeast expects the transaction Commit to be triggered n times or more.
+func (e *ExpectedCommit) AtLeast(n int) *ExpectedCommit {
+	e.atLeast(n)
+	return e
+}
+
+// AtMost expects the transaction Commit to be triggered no more than n times.
+func (e *ExpectedCommit) AtMost(n int) *ExpectedCommit {
+	e.atMost(n)
+	return e
+}
+
+// AnyTimes allows the transaction Commit to be triggered any number of times.
+func (e *ExpectedCommit) AnyTimes() *ExpectedCommit {
+	e.setCalls(0, unlimited)
+	return e
+}
+
+// Maybe allows the transaction Commit to be triggered once or not at all.
+func (e *ExpectedCommit) Maybe() *ExpectedCommit {
+	e.setCalls(0, 1)
+	return e
+}
+
+// Times expects the transaction Rollback to be triggered exactly n times.
+func (e *ExpectedRollback) Times(n int) *ExpectedRollback {
+	e.times(n)
+	return e
+}
+
+// AtLeast expects the transaction Rollback to be triggered n times or more.
+func (e *ExpectedRollback) AtLeast(n int) *ExpectedRollback {
+	e.atLeast(n)
+	return e
+}
+
+// AtMost expects the transaction Rollback to be triggered no more than n times.
+func (e *ExpectedRollback) AtMost(n int) *ExpectedRollback {
+	e.atMost(n)
+	return e
+}
+
+// AnyTimes allows the transaction Rollback to be triggered any number of times.
+func (e *ExpectedRollback) AnyTimes() *ExpectedRollback {
+	e.setCalls(0, unlimited)
+	return e
+}
+
+// Maybe allows the transaction Rollback to be triggered once or not at all.
+func (e *ExpectedRollback) Maybe() *ExpectedRollback {
+	e.setCalls(0, 1)
+	return e
+}
+
+// Times expects the query to be triggered exactly n times.
+func (e *ExpectedQuery) Times(n int) *ExpectedQuery {
+	e.times(n)
+	return e
+}
+
+// AtLeast expects the query to be triggered n times or more.
+func (e *ExpectedQuery) AtLeast(n int) *ExpectedQuery {
+	e.atLeast(n)
+	return e
+}
+
+// AtMost expects the query to be triggered no more than n times.
+func (e *ExpectedQuery) AtMost(n int) *ExpectedQuery {
+	e.atMost(n)
+	return e
+}
+
+// AnyTimes allows the query to be triggered any number of times.
+func (e *ExpectedQuery) AnyTimes() *ExpectedQuery {
+	e.setCalls(0, unlimited)
+	return e
+}
+
+// Maybe allows the query to be triggered once or not at all.
+func (e *ExpectedQuery) Maybe() *ExpectedQuery {
+	e.setCalls(0, 1)
+	return e
+}
+
+// Times expects the exec action to be triggered exactly n times.
+func (e *ExpectedExec) Times(n int) *ExpectedExec {
+	e.times(n)
+	return e
+}
+
+// AtLeast expects the exec action to be triggered n times or more.
+func (e *ExpectedExec) AtLeast(n int) *ExpectedExec {
+	e.atLeast(n)
+	return e
+}
+
+// AtMost expects the exec action to be triggered no more than n times.
+func (e *ExpectedExec) AtMost(n int) *ExpectedExec {
+	e.atMost(n)
+	return e
+}
+
+// AnyTimes allows the exec action to be triggered any number of times.
+func (e *ExpectedExec) AnyTimes() *ExpectedExec {
+	e.setCalls(0, unlimited)
+	return e
+}
+
+// Maybe allows the exec action to be triggered once or not at all.
+func (e *ExpectedExec) Maybe() *ExpectedExec {
+	e.setCalls(0, 1)
+	return e
+}
+
+// Times expects the Prepare statement to be triggered exactly n times.
+func (e *ExpectedPrepare) Times(n int) *ExpectedPrepare {
+	e.times(n)
+	return e
+}
+
+// AtLeast expects the Prepare statement to be triggered n times or more.
+func (e *ExpectedPrepare) AtLeast(n int) *ExpectedPrepare {
+	e.atLeast(n)
+	return e
+}
+
+// AtMost expects the Prepare statement to be triggered no more than n times.
+func (e *ExpectedPrepare) AtMost(n int) *ExpectedPrepare {
+	e.atMost(n)
+	return e
+}
+
+// AnyTimes allows the Prepare statement to be triggered any number of times.
+func (e *ExpectedPrepare) AnyTimes() *ExpectedPrepare {
+	e.setCalls(0, unlimited)
+	return e
+}
+
+// Maybe allows the Prepare statement to be triggered once or not at all.
+func (e *ExpectedPrepare) Maybe() *ExpectedPrepare {
+	e.setCalls(0, 1)
+	return e
+}
+
+// Times expects the database Ping to be triggered exactly n times.
+func (e *ExpectedPing) Times(n int) *ExpectedPing {
+	e.times(n)
+	return e
+}
+
+// AtLeast expects the database Ping to be triggered n times or more.
+func (e *ExpectedPing) AtLeast(n int) *ExpectedPing {
+	e.atLeast(n)
+	return e
+}
+
+// AtMost expects the database Ping to be triggered no more than n times.
+func (e *ExpectedPing) AtMost(n int) *ExpectedPing {
+	e.atMost(n)
+	return e
+}
+
+// AnyTimes allows the database Ping to be triggered any number of times.
+func (e *ExpectedPing) AnyTimes() *ExpectedPing {
+	e.setCalls(0, unlimited)
+	return e
+}
+
+// Maybe allows the database Ping to be triggered once or not at all.
+func (e *ExpectedPing) Maybe() *ExpectedPing {
+	e.setCalls(0, 1)
+	return e
+}
+
+// Times expects the connection ResetSession to be triggered exactly n times.
+func (e *ExpectedResetSession) Times(n int) *ExpectedResetSession {
+	e.times(n)
+	return e
+}
+
+// AtLeast expects the connection ResetSession to be triggered n times or more.
+func (e *ExpectedResetSession) AtLeast(n int) *ExpectedResetSession {
+	e.atLeast(n)
+	return e
+}
+
+// AtMost expects the connection ResetSession to be triggered no more than n times.
+func (e *ExpectedResetSession) AtMost(n int) *ExpectedResetSession {
+	e.atMost(n)
+	return e
+}
+
+// AnyTimes allows the connection ResetSession to be triggered any number of times.
+func (e *ExpectedResetSession) AnyTimes() *ExpectedResetSession {
+	e.setCalls(0, unlimited)
+	return e
+}
+
+// Maybe allows the connection ResetSession to be triggered once or not at all.
+func (e *ExpectedResetSession) Maybe() *ExpectedResetSession {
+	e.setCalls(0, 1)
+	return e
+}
@@ -0,0 +1,202 @@
+package sqlmock
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func ExampleExpectedExec_Times() {
+	db, mock, _ := New()
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO users").WillReturnResult(NewResult(1, 1)).Times(3)
+
+	for i := 0; i < 3; i++ {
+		if _, err := db.Exec("INSERT INTO users(name) VALUES (?)", fmt.Sprintf("user%d", i)); err != nil {
+			fmt.Println(err)
+		}
+	}
+	fmt.Println(mock.ExpectationsWereMet())
+	// Output: <nil>
+}
+
+func TestRepeatableExecTimes(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO users").WillReturnResult(NewResult(1, 1)).Times(2)
+	mock.ExpectCommit()
+
+	for i := 0; i < 2; i++ {
+		if _, err := db.Exec("INSERT INTO users(name) VALUES (?)", "john"); err != nil {
+			t.Fatalf("unexpected error on exec %d: %s", i, err)
+		}
+	}
+	if _, err := db.Exec("INSERT INTO users(name) VALUES (?)", "john"); err == nil {
+		t.Fatal("expected an error, since exec was expected only twice")
+	}
+}
+
+func TestRepeatableNotFulfilledReportsCalls(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO users").WillReturnResult(NewResult(1, 1)).Times(3)
+
+	if _, err := db.Exec("INSERT INTO users(name) VALUES (?)", "john"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = mock.ExpectationsWereMet()
+	if err == nil {
+		t.Fatal("expected an error, since exec was called only once")
+	}
+	if !strings.Contains(err.Error(), "called 1 times, expected exactly 3") {
+		t.Errorf("expected error to report calls, but got: %s", err)
+	}
+}
+
+func TestRepeatableAnyTimesDoesNotBlockOrder(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT name FROM users").
+		WillReturnRows(NewRows([]string{"name"}).AddRow("john").AddRow("jane")).
+		AnyTimes()
+	mock.ExpectExec("UPDATE users").WillReturnResult(NewResult(0, 2))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+	for i := 0; i < 3; i++ {
+		rows, err := tx.Query("SELECT name FROM users")
+		if err != nil {
+			t.Fatalf("unexpected error on query %d: %s", i, err)
+		}
+		var n int
+		for rows.Next() {
+			n++
+		}
+		rows.Close()
+		if n != 2 {
+			t.Errorf("expected every query to return 2 rows, but query %d got %d", i, n)
+		}
+	}
+	if _, err := tx.Exec("UPDATE users SET active = 1"); err != nil {
+		t.Fatalf("unexpected error on exec: %s", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("unexpected error on commit: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRepeatableClosedOnceFollowingTriggered(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO users").WillReturnResult(NewResult(1, 1)).AtLeast(1)
+	mock.ExpectExec("UPDATE users").WillReturnResult(NewResult(0, 1))
+
+	if _, err := db.Exec("INSERT INTO users(name) VALUES (?)", "john"); err != nil {
+		t.Fatalf("unexpected error on insert: %s", err)
+	}
+	if _, err := db.Exec("UPDATE users SET name = ?", "jane"); err != nil {
+		t.Fatalf("unexpected error on update: %s", err)
+	}
+	if _, err := db.Exec("INSERT INTO users(name) VALUES (?)", "bob"); err == nil {
+		t.Error("expected an error, since the insert was expected before the update")
+	}
+	if err := mock.ExpectationsWereMet(); err == nil {
+		t.Error("expected the insert after the update to be reported as unexpected")
+	}
+}
+
+func TestRepeatableMaybe(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("DELETE FROM sessions").WillReturnResult(NewResult(0, 0)).Maybe()
+	mock.ExpectBegin()
+
+	if _, err := db.Begin(); err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRepeatableAtLeastAtMost(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.MatchExpectationsInOrder(false)
+
+	mock.ExpectExec("INSERT INTO users").WillReturnResult(NewResult(1, 1)).AtLeast(2).AtMost(3)
+
+	for i := 0; i < 3; i++ {
+		if _, err := db.Exec("INSERT INTO users(name) VALUES (?)", "john"); err != nil {
+			t.Fatalf("unexpected error on exec %d: %s", i, err)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+	if _, err := db.Exec("INSERT INTO users(name) VALUES (?)", "john"); err == nil {
+		t.Fatal("expected an error, since exec was expected at most 3 times")
+	}
+}
+
+func TestRepeatableCallsDescription(t *testing.T) {
+	cases := []struct {
+		setup    func(e *ExpectedPing)
+		expected string
+	}{
+		{func(e *ExpectedPing) {}, ""},
+		{func(e *ExpectedPing) { e.Times(2) }, "called 0 times, expected exactly 2"},
+		{func(e *ExpectedPing) { e.AtLeast(1) }, "called 0 times, expected at least 1"},
+		{func(e *ExpectedPing) { e.AtMost(4) }, "called 0 times, expected at most 4"},
+		{func(e *ExpectedPing) { e.AtMost(4).AtLeast(2) }, "called 0 times, expected between 2 and 4"},
+		{func(e *ExpectedPing) { e.Maybe() }, "called 0 times, expected at most 1"},
+		{func(e *ExpectedPing) { e.AnyTimes() }, "called 0 times, expected at least 0"},
+	}
+
+	for i, c := range cases {
+		e := &ExpectedPing{}
+		c.setup(e)
+		if actual := e.calls(); actual != c.expected {
+			t.Errorf("case %d: expected calls description %q, but got %q", i, c.expected, actual)
+		}
+	}
+}
@@ -24,6 +24,76 @@ func QueryMatcherOption(queryMatcher QueryMatcher) SqlMockOption {
 	}
 }
 
+// PlaceholderStyleOption configures the style of the query placeholders
+// used by the mocked driver. The placeholders of that style are counted,
+// so that prepared statements report the number of arguments they expect
+// and a query or exec called with a different number of arguments fails,
+// before it is matched against the expectations.
+//
+// The placeholders are not parsed by default, see PlaceholderNone.
+func PlaceholderStyleOption(style PlaceholderStyle) SqlMockOption {
+	return func(s *sqlmock) error {
+		s.placeholders = style
+		return nil
+	}
+}
+
+// ReportUnexpectedCallsOption determines whether ExpectationsWereMet
+// reports the calls, which did not match any expectation.
+//
+// By default the calls rejected by the mock are reported, so that a test
+// fails even if the code under test ignored the returned error. Passing
+// false restores the lenient behavior, where only the queued expectations
+// are checked.
+func ReportUnexpectedCallsOption(report bool) SqlMockOption {
+	return func(s *sqlmock) error {
+		s.ignoreUnexpected = !report
+		return nil
+	}
+}
+
+// FailOnUnexpectedCallOption determines whether a call, which did not
+// match any expectation, fails the test right away with the stack of the
+// code which made the call, instead of being reported by ExpectationsWereMet.
+// It only has an effect on mocks created with NewT.
+func FailOnUnexpectedCallOption(fail bool) SqlMockOption {
+	return func(s *sqlmock) error {
+		s.failOnUnexpected = fail
+		return nil
+	}
+}
+
+// MultipleConnectionsOption determines whether every connection opened by
+// database/sql is a distinct mocked connection, which must be expected.
+//
+// If true is passed, the connections are identified by their ID, starting
+// from 1, in the call history and each of them must be expected using the
+// ExpectConnect() method on the mock, so that connection pooling, db.Conn
+// and session state could be tested. The database is not pinged on creation
+// then, so that the first connection could be expected as well.
+func MultipleConnectionsOption(enable bool) SqlMockOption {
+	return func(s *sqlmock) error {
+		s.multipleConns = enable
+		return nil
+	}
+}
+
+// MonitorSessionResetsOption determines whether the session resets, which
+// database/sql does before reusing a connection from the pool, should be
+// observed and mocked.
+//
+// If true is passed, we will check these resets were expected. Expectations
+// can be registered using the ExpectResetSession() method on the mock.
+//
+// If false is passed or this option is omitted, session resets always succeed,
+// unless a call on the connection returned driver.ErrBadConn.
+func MonitorSessionResetsOption(monitorResets bool) SqlMockOption {
+	return func(s *sqlmock) error {
+		s.monitorResets = monitorResets
+		return nil
+	}
+}
+
 // MonitorPingsOption determines whether calls to Ping on the driver should be
 // observed and mocked.
 //
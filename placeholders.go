@@ -0,0 +1,80 @@
+package sqlmock
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// PlaceholderStyle is the style of the query placeholders of a
+// database driver, it is used to count the arguments a query expects.
+type PlaceholderStyle int
+
+const (
+	// PlaceholderNone does not parse the placeholders, the statements
+	// accept any number of arguments. It is the default style.
+	PlaceholderNone PlaceholderStyle = iota
+	// PlaceholderQuestion counts the ? placeholders, as used by MySQL
+	// and SQLite.
+	PlaceholderQuestion
+	// PlaceholderDollar counts the $1, $2... placeholders up to the
+	// highest number, as used by PostgreSQL.
+	PlaceholderDollar
+	// PlaceholderColon counts the distinct :name and :1 placeholders, as
+	// used by Oracle.
+	PlaceholderColon
+	// PlaceholderAt counts the distinct @name placeholders, as used by
+	// SQL Server.
+	PlaceholderAt
+)
+
+// countPlaceholders returns the number of arguments the query expects
+// by its placeholders of the given style, or -1 if the placeholders
+// are not parsed or the query cannot be tokenized
+func countPlaceholders(query string, style PlaceholderStyle) int {
+	if style == PlaceholderNone {
+		return -1
+	}
+	tokens, err := tokenizeSQL(query)
+	if err != nil {
+		return -1
+	}
+
+	var count int
+	names := make(map[string]bool)
+	for _, tok := range tokens {
+		if tok.kind != tokenPlaceholder {
+			continue
+		}
+		switch prefix := tok.text[0]; {
+		case style == PlaceholderQuestion && prefix == '?':
+			if n, err := strconv.Atoi(tok.text[1:]); err == nil {
+				// numbered ?NNN refers to an argument by position
+				if n > count {
+					count = n
+				}
+				continue
+			}
+			count++
+		case style == PlaceholderDollar && prefix == '$':
+			if n, _ := strconv.Atoi(tok.text[1:]); n > count {
+				count = n
+			}
+		case style == PlaceholderColon && prefix == ':',
+			style == PlaceholderAt && prefix == '@':
+			if !names[tok.text] {
+				names[tok.text] = true
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// checkArgCount verifies the number of arguments passed against
+// the placeholders of the query, if they are parsed
+func (c *sqlmock) checkArgCount(query string, args int) error {
+	if want := countPlaceholders(query, c.placeholders); want >= 0 && want != args {
+		return fmt.Errorf("expected %d arguments by the query placeholders, but got %d", want, args)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package sqlmock
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCountPlaceholders(t *testing.T) {
+	cases := []struct {
+		query    string
+		style    PlaceholderStyle
+		expected int
+	}{
+		{"SELECT * FROM users WHERE id = ?", PlaceholderNone, -1},
+		{"SELECT * FROM users WHERE id = ? AND name = ?", PlaceholderQuestion, 2},
+		{"SELECT * FROM users WHERE name = '?' AND id = ? -- ?", PlaceholderQuestion, 1},
+		{"SELECT * FROM users WHERE id = ?2 OR parent = ?1", PlaceholderQuestion, 2},
+		{"SELECT * FROM users WHERE id = $1 OR parent = $1 AND created > $3", PlaceholderDollar, 3},
+		{"SELECT $$$1$$, created::date FROM users", PlaceholderDollar, 0},
+		{"SELECT * FROM users WHERE id = :id OR parent = :id AND name = :name", PlaceholderColon, 2},
+		{"SELECT * FROM users WHERE id = :1 OR parent = :1 AND name = :2", PlaceholderColon, 2},
+		{"SELECT tags[1:2], created::date FROM users WHERE id = :id", PlaceholderColon, 1},
+		{"SELECT @@ROWCOUNT, name FROM users WHERE id = @id AND age > @p2", PlaceholderAt, 2},
+		{"SELECT * FROM users WHERE id = $1 AND name = ?", PlaceholderQuestion, 1},
+		{"SELECT 'unterminated", PlaceholderQuestion, -1},
+	}
+
+	for i, c := range cases {
+		if actual := countPlaceholders(c.query, c.style); actual != c.expected {
+			t.Errorf("expected %d placeholders, but got %d at %d case", c.expected, actual, i)
+		}
+	}
+}
+
+func TestPreparedStatementNumInput(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New(PlaceholderStyleOption(PlaceholderDollar))
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectPrepare("SELECT name FROM users").
+		ExpectQuery().
+		WillReturnRows(NewRows([]string{"name"}))
+
+	stmt, err := db.Prepare("SELECT name FROM users WHERE id = $1 AND age > $2 AND active = $3")
+	if err != nil {
+		t.Fatalf("unexpected error on prepare: %s", err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Query(1, 18)
+	if err == nil || err.Error() != "sql: expected 3 arguments, got 2" {
+		t.Errorf("expected an argument count error, but got: %v", err)
+	}
+
+	rows, err := stmt.Query(1, 18, true)
+	if err != nil {
+		t.Fatalf("error '%s' was not expected, while querying rows", err)
+	}
+	rows.Close()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestExecArgumentCountMismatch(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New(PlaceholderStyleOption(PlaceholderQuestion))
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE users").WillReturnResult(NewResult(0, 1))
+
+	_, err = db.Exec("UPDATE users SET name = ? WHERE id = ?", "john")
+	expected := "ExecQuery 'UPDATE users SET name = ? WHERE id = ?', expected 2 arguments by the query placeholders, but got 1"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, but got: %v", expected, err)
+	}
+	if len(mock.UnexpectedCalls()) != 1 {
+		t.Errorf("expected the exec to be reported as unexpected, but got: %v", mock.UnexpectedCalls())
+	}
+
+	if _, err = db.Exec("UPDATE users SET name = ? WHERE id = ?", "john", 1); err != nil {
+		t.Errorf("error '%s' was not expected, while updating a row", err)
+	}
+	if err := mock.ExpectationsWereMet(); err == nil || !strings.Contains(err.Error(), "expected 2 arguments") {
+		t.Errorf("expected the rejected exec to be reported, but got: %v", err)
+	}
+}
+
+func TestPositionalColonPlaceholders(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New(PlaceholderStyleOption(PlaceholderColon))
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT name FROM users").WithArgs(5).WillReturnRows(NewRows([]string{"name"}).AddRow("john"))
+
+	var name string
+	if err := db.QueryRow("SELECT name FROM users WHERE id = :1", 5).Scan(&name); err != nil {
+		t.Errorf("error '%s' was not expected, while querying a row", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
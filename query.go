@@ -69,3 +69,44 @@ var QueryMatcherEqual QueryMatcher = QueryMatcherFunc(func(expectedSQL, actualSQ
 	}
 	return nil
 })
+
+// QueryMatcherSQL is the SQL query matcher, which compares
+// expected and actual SQL token by token. It is not sensitive
+// to whitespace, comments, keyword and identifier case, identifier
+// quoting, trailing semicolons or the placeholder style, so that
+// ?, $1, :name and @p1 are all equal. String and numeric literals
+// must be the same.
+var QueryMatcherSQL QueryMatcher = QueryMatcherFunc(func(expectedSQL, actualSQL string) error {
+	expect, err := tokenizeSQL(expectedSQL)
+	if err != nil {
+		return fmt.Errorf("could not tokenize expected sql: %s", err)
+	}
+	actual, err := tokenizeSQL(actualSQL)
+	if err != nil {
+		return fmt.Errorf("could not tokenize actual sql: %s", err)
+	}
+	expect, actual = trimSemicolons(expect), trimSemicolons(actual)
+
+	for i := 0; i < len(expect) || i < len(actual); i++ {
+		switch {
+		case i == len(actual):
+			return fmt.Errorf(`actual sql: "%s" does not match expected "%s": expected %q at the end`,
+				stripQuery(actualSQL), stripQuery(expectedSQL), expect[i].text)
+		case i == len(expect):
+			return fmt.Errorf(`actual sql: "%s" does not match expected "%s": unexpected %q at position %d`,
+				stripQuery(actualSQL), stripQuery(expectedSQL), actual[i].text, actual[i].pos)
+		case expect[i].kind != actual[i].kind || expect[i].normalized() != actual[i].normalized():
+			return fmt.Errorf(`actual sql: "%s" does not match expected "%s": expected %q, but got %q at position %d`,
+				stripQuery(actualSQL), stripQuery(expectedSQL), expect[i].text, actual[i].text, actual[i].pos)
+		}
+	}
+	return nil
+})
+
+// trimSemicolons removes the semicolons ending the statement
+func trimSemicolons(tokens []sqlToken) []sqlToken {
+	for len(tokens) > 0 && tokens[len(tokens)-1].text == ";" {
+		tokens = tokens[:len(tokens)-1]
+	}
+	return tokens
+}
@@ -122,3 +122,45 @@ func TestQueryMatcherEqual(t *testing.T) {
 		}
 	}
 }
+
+func TestQueryMatcherSQL(t *testing.T) {
+	type testCase struct {
+		expected string
+		actual   string
+		err      error
+	}
+
+	cases := []testCase{
+		{"SELECT name, email FROM users WHERE id = ?", "select name,email\n from users -- all of them\n where id=$1;", nil},
+		{`SELECT "name" FROM [users] WHERE id IN (?, ?)`, "SELECT `name` /* quoted */ FROM users WHERE id IN (:first, @p2)", nil},
+		{"SELECT * FROM users WHERE created::date > $1", "SELECT * FROM users WHERE created :: date > ?", nil},
+		{"UPDATE users SET name = 'John' WHERE id = 1.5e3", "update users set name = 'John' where id = 1.5e3", nil},
+		{"SELECT arr[1], tags[1:2] FROM t", "select arr[ 1 ], tags[1 : 2] from t", nil},
+		{"INSERT INTO users(name) VALUES ('it''s')", "INSERT INTO users (name) VALUES ('it''s');;", nil},
+		{"SELECT name FROM users", "SELECT name FROM users WHERE id = ?", fmt.Errorf(`actual sql: "SELECT name FROM users WHERE id = ?" does not match expected "SELECT name FROM users": unexpected "WHERE" at position 23`)},
+		{"SELECT name FROM users WHERE id = ?", "SELECT name FROM users", fmt.Errorf(`actual sql: "SELECT name FROM users" does not match expected "SELECT name FROM users WHERE id = ?": expected "WHERE" at the end`)},
+		{"SELECT name FROM users", "SELECT email FROM users", fmt.Errorf(`actual sql: "SELECT email FROM users" does not match expected "SELECT name FROM users": expected "name", but got "email" at position 7`)},
+		{"SELECT 'John'", "SELECT 'john'", fmt.Errorf(`actual sql: "SELECT 'john'" does not match expected "SELECT 'John'": expected "'John'", but got "'john'" at position 7`)},
+		{"SELECT ?", "SELECT 'x'", fmt.Errorf(`actual sql: "SELECT 'x'" does not match expected "SELECT ?": expected "?", but got "'x'" at position 7`)},
+		{"SELECT 'x", "SELECT 'x'", fmt.Errorf(`could not tokenize expected sql: unterminated string at position 7`)},
+		{"SELECT 1", "SELECT 1 /* comment", fmt.Errorf(`could not tokenize actual sql: unterminated comment at position 9`)},
+	}
+
+	for i, c := range cases {
+		err := QueryMatcherSQL.Match(c.expected, c.actual)
+		if err == nil && c.err != nil {
+			t.Errorf(`got no error, but expected "%v" at %d case`, c.err, i)
+			continue
+		}
+		if err != nil && c.err == nil {
+			t.Errorf(`got unexpected error "%v" at %d case`, err, i)
+			continue
+		}
+		if err == nil {
+			continue
+		}
+		if err.Error() != c.err.Error() {
+			t.Errorf(`expected error "%v", but got "%v" at %d case`, c.err, err, i)
+		}
+	}
+}
@@ -83,6 +83,21 @@ func (rs *rowSets) String() string {
 	return strings.TrimSpace(msg)
 }
 
+// clone returns a copy of the row sets positioned before the first row,
+// so that every call of a repeatable query iterates all of the rows
+func (rs *rowSets) clone() *rowSets {
+	sets := make([]*Rows, len(rs.sets))
+	for i, set := range rs.sets {
+		if set == nil {
+			continue
+		}
+		cp := *set
+		cp.pos = 0
+		sets[i] = &cp
+	}
+	return &rowSets{sets: sets, ex: rs.ex}
+}
+
 func (rs *rowSets) empty() bool {
 	for _, set := range rs.sets {
 		if len(set.rows) > 0 {
@@ -0,0 +1,330 @@
+package sqlmock
+
+import (
+	"database/sql/driver"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// CSVOption configures how Rows.FromCSV reads the records
+type CSVOption func(*csvConfig)
+
+type csvConfig struct {
+	comma      rune
+	comment    rune
+	lazyQuotes bool
+	header     bool
+	null       string
+	layouts    []string
+	parsers    map[string]func(string) (driver.Value, error)
+	columns    []*Column
+}
+
+func newCSVConfig(opts []CSVOption) *csvConfig {
+	cfg := &csvConfig{
+		comma:   ',',
+		layouts: []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02"},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// CSVDelimiter sets the field delimiter, which is a comma by default.
+func CSVDelimiter(delimiter rune) CSVOption {
+	return func(c *csvConfig) {
+		c.comma = delimiter
+	}
+}
+
+// CSVComment makes the lines starting with the given
+// character to be ignored as comments.
+func CSVComment(comment rune) CSVOption {
+	return func(c *csvConfig) {
+		c.comment = comment
+	}
+}
+
+// CSVLazyQuotes allows quotes to appear in unquoted fields
+// and non doubled quotes to appear in quoted fields.
+func CSVLazyQuotes() CSVOption {
+	return func(c *csvConfig) {
+		c.lazyQuotes = true
+	}
+}
+
+// CSVHeader makes the first record name the columns of the
+// rows. If the rows were created with column definitions,
+// they are looked up by the names in the header.
+func CSVHeader() CSVOption {
+	return func(c *csvConfig) {
+		c.header = true
+	}
+}
+
+// CSVNull sets the text of NULL cells, which is
+// case insensitive NULL by default.
+func CSVNull(null string) CSVOption {
+	return func(c *csvConfig) {
+		c.null = null
+	}
+}
+
+// CSVTimeLayouts sets the layouts tried in order to parse the
+// time.Time cells, RFC 3339, "2006-01-02 15:04:05" with
+// optional fractional seconds and "2006-01-02" by default.
+func CSVTimeLayouts(layouts ...string) CSVOption {
+	return func(c *csvConfig) {
+		c.layouts = layouts
+	}
+}
+
+// CSVParser sets the parser of the cells in the named column, it
+// takes precedence over the type of the column definition.
+func CSVParser(column string, parser func(string) (driver.Value, error)) CSVOption {
+	return func(c *csvConfig) {
+		if c.parsers == nil {
+			c.parsers = make(map[string]func(string) (driver.Value, error))
+		}
+		c.parsers[column] = parser
+	}
+}
+
+// CSVColumns sets the column definitions of the rows, as if they
+// were created with NewRowsWithColumnDefinition, so that the cells
+// are parsed by the column types.
+func CSVColumns(columns ...*Column) CSVOption {
+	return func(c *csvConfig) {
+		c.columns = columns
+	}
+}
+
+// FromCSV reads the rows from CSV records and returns the same
+// instance to perform subsequent actions.
+//
+// The cells are parsed by the column parsers set with CSVParser, or
+// by the scan type of the column definitions, if the rows were created
+// with NewRowsWithColumnDefinition, so that int64, float64, bool,
+// string, []byte and time.Time values are produced. sql.Null* scan
+// types and pointers are parsed as the type they hold. Other cells
+// are parsed by CSVColumnParser, as in FromCSVString.
+//
+// Unlike FromCSVString, it does not panic, but returns an error with
+// the row and column of the cell, which could not be parsed. No rows
+// are added then.
+func (r *Rows) FromCSV(rd io.Reader, opts ...CSVOption) (*Rows, error) {
+	cfg := newCSVConfig(opts)
+	if cfg.columns != nil {
+		r.setColumns(cfg.columns)
+	}
+
+	reader := csv.NewReader(rd)
+	reader.Comma = cfg.comma
+	reader.Comment = cfg.comment
+	reader.LazyQuotes = cfg.lazyQuotes
+	// allow quoted fields after a delimiter and spaces, unless
+	// the delimiter is a space, which would skip empty fields
+	reader.TrimLeadingSpace = !unicode.IsSpace(cfg.comma)
+	reader.FieldsPerRecord = -1
+
+	cols, def := r.cols, r.def
+	if cfg.header {
+		header, err := reader.Read()
+		if err != nil {
+			return r, fmt.Errorf("reading CSV header: %s", err)
+		}
+		if cols, def, err = r.headerColumns(header); err != nil {
+			return r, err
+		}
+	}
+
+	parsers := make([]func(string) (driver.Value, error), len(cols))
+	for i, col := range cols {
+		if p, ok := cfg.parsers[col]; ok {
+			parsers[i] = p
+		} else if def != nil && def[i].ScanType() != nil {
+			parsers[i] = cfg.typedParser(def[i].ScanType())
+		}
+	}
+
+	var rows [][]driver.Value
+	for n := 1; ; n++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return r, fmt.Errorf("reading CSV: %s", err)
+		}
+		if len(record) != len(cols) {
+			return r, fmt.Errorf("row %d: expected %d columns, but got %d", n, len(cols), len(record))
+		}
+
+		row := make([]driver.Value, len(cols))
+		for i, cell := range record {
+			cell = strings.TrimSpace(cell)
+			if parsers[i] == nil {
+				row[i] = CSVColumnParser(cell)
+				continue
+			}
+			if cfg.isNull(cell) {
+				continue
+			}
+			if row[i], err = parsers[i](cell); err != nil {
+				return r, fmt.Errorf("row %d, column %d (%q): %s", n, i+1, cols[i], err)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	r.cols, r.def = cols, def
+	r.rows = append(r.rows, rows...)
+	return r, nil
+}
+
+// setColumns sets the column definitions of the rows
+func (r *Rows) setColumns(columns []*Column) {
+	r.cols = make([]string, len(columns))
+	for i, column := range columns {
+		r.cols[i] = column.Name()
+	}
+	r.def = columns
+}
+
+// FromTSV reads the rows from tab separated records,
+// see FromCSV.
+func (r *Rows) FromTSV(rd io.Reader, opts ...CSVOption) (*Rows, error) {
+	return r.FromCSV(rd, append([]CSVOption{CSVDelimiter('\t')}, opts...)...)
+}
+
+// headerColumns returns the columns named by the header, with their
+// definitions in the same order, if the rows have definitions
+func (r *Rows) headerColumns(header []string) ([]string, []*Column, error) {
+	cols := make([]string, len(header))
+	for i, name := range header {
+		cols[i] = strings.TrimSpace(name)
+	}
+	if len(r.rows) > 0 && !reflect.DeepEqual(cols, r.cols) {
+		return nil, nil, fmt.Errorf("CSV header %v does not match the columns %v of the rows added before", cols, r.cols)
+	}
+	if r.def == nil {
+		return cols, nil, nil
+	}
+
+	def := make([]*Column, len(cols))
+	for i, name := range cols {
+		for _, column := range r.def {
+			if column.Name() == name {
+				def[i] = column
+				break
+			}
+		}
+		if def[i] == nil {
+			return nil, nil, fmt.Errorf("CSV header column %d (%q) is not defined", i+1, name)
+		}
+	}
+	return cols, def, nil
+}
+
+func (cfg *csvConfig) isNull(cell string) bool {
+	if cfg.null != "" {
+		return cell == cfg.null
+	}
+	return strings.EqualFold(cell, "null")
+}
+
+var (
+	valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	timeType   = reflect.TypeOf(time.Time{})
+	bytesType  = reflect.TypeOf([]byte(nil))
+)
+
+// typedParser returns the parser producing the driver value of
+// the scan type, sql.Null* types and pointers are parsed as the
+// type they hold
+func (cfg *csvConfig) typedParser(typ reflect.Type) func(string) (driver.Value, error) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if held, ok := nullableValue(typ); ok {
+		typ = held
+	}
+
+	switch {
+	case typ == timeType:
+		return cfg.parseTime
+	case typ.ConvertibleTo(bytesType) && typ.Kind() == reflect.Slice:
+		return func(s string) (driver.Value, error) { return []byte(s), nil }
+	}
+
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(s string) (driver.Value, error) {
+			n, err := strconv.ParseInt(s, 10, typ.Bits())
+			return n, numError(err)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(s string) (driver.Value, error) {
+			n, err := strconv.ParseUint(s, 10, typ.Bits())
+			if err == nil && n > 1<<63-1 {
+				return nil, fmt.Errorf("%s overflows int64", s)
+			}
+			return int64(n), numError(err)
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(s string) (driver.Value, error) {
+			f, err := strconv.ParseFloat(s, typ.Bits())
+			return f, numError(err)
+		}
+	case reflect.Bool:
+		return func(s string) (driver.Value, error) {
+			b, err := strconv.ParseBool(s)
+			return b, numError(err)
+		}
+	case reflect.String:
+		return func(s string) (driver.Value, error) { return s, nil }
+	}
+	return func(s string) (driver.Value, error) {
+		return CSVColumnParser(s), nil
+	}
+}
+
+func (cfg *csvConfig) parseTime(s string) (driver.Value, error) {
+	for _, layout := range cfg.layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("%q does not match the time layouts %q", s, cfg.layouts)
+}
+
+// nullableValue returns the type held by a sql.Null* like struct,
+// which is a driver.Valuer with a Valid field and a value field
+func nullableValue(typ reflect.Type) (reflect.Type, bool) {
+	if typ.Kind() != reflect.Struct || typ.NumField() != 2 || !reflect.PtrTo(typ).Implements(valuerType) {
+		return nil, false
+	}
+	for i := 0; i < 2; i++ {
+		if f := typ.Field(i); f.Name == "Valid" && f.Type.Kind() == reflect.Bool {
+			return typ.Field(1 - i).Type, true
+		}
+	}
+	return nil, false
+}
+
+// numError strips the function name from strconv errors
+func numError(err error) error {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		return fmt.Errorf("parsing %q: %s", numErr.Num, numErr.Err)
+	}
+	return err
+}
@@ -0,0 +1,145 @@
+//go:build go1.8
+// +build go1.8
+
+package sqlmock
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRowsFromCSVWithColumnDefinition(t *testing.T) {
+	rows, err := NewRowsWithColumnDefinition(
+		NewColumn("id").OfType("BIGINT", int64(0)),
+		NewColumn("score").OfType("DOUBLE", 0.0),
+		NewColumn("active").OfType("BOOL", false),
+		NewColumn("name").OfType("VARCHAR", ""),
+		NewColumn("email").OfType("VARCHAR", sql.NullString{}),
+		NewColumn("created").OfType("TIMESTAMP", time.Time{}),
+		NewColumn("raw").OfType("BLOB", []byte(nil)),
+	).FromCSV(strings.NewReader(`
+1, 1.5, true, john, "john@example.com", 2026-10-16T10:00:00Z, a
+2, 0, false, "doe, jane", NULL, 2026-10-16, null
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := [][]driver.Value{
+		{int64(1), 1.5, true, "john", "john@example.com", time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC), []byte("a")},
+		{int64(2), 0.0, false, "doe, jane", nil, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), nil},
+	}
+	if !reflect.DeepEqual(rows.rows, expected) {
+		t.Errorf("expected rows %v, but got %v", expected, rows.rows)
+	}
+}
+
+func TestRowsFromTSVWithHeader(t *testing.T) {
+	upper := func(s string) (driver.Value, error) {
+		return strings.ToUpper(s), nil
+	}
+	rows, err := NewRowsWithColumnDefinition(
+		NewColumn("id").OfType("INT", int32(0)),
+		NewColumn("name").OfType("VARCHAR", ""),
+	).FromTSV(strings.NewReader("name\tid\n# comment\njohn\t1\n-\t2\n"),
+		CSVHeader(), CSVComment('#'), CSVNull("-"), CSVParser("name", upper))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if expected := []string{"name", "id"}; !reflect.DeepEqual(rows.cols, expected) {
+		t.Errorf("expected columns %v, but got %v", expected, rows.cols)
+	}
+	if rows.def[0].Name() != "name" || rows.def[1].Name() != "id" {
+		t.Errorf("expected column definitions to follow the header")
+	}
+	expected := [][]driver.Value{{"JOHN", int64(1)}, {nil, int64(2)}}
+	if !reflect.DeepEqual(rows.rows, expected) {
+		t.Errorf("expected rows %v, but got %v", expected, rows.rows)
+	}
+}
+
+func TestRowsFromCSVWithoutDefinition(t *testing.T) {
+	rows, err := NewRows(nil).FromCSV(strings.NewReader("id;title\n5;hello world\n"), CSVHeader(), CSVDelimiter(';'))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := [][]driver.Value{{[]byte("5"), []byte("hello world")}}
+	if !reflect.DeepEqual(rows.rows, expected) {
+		t.Errorf("expected rows %v, but got %v", expected, rows.rows)
+	}
+}
+
+func TestRowsFromCSVErrors(t *testing.T) {
+	define := func() *Rows {
+		return NewRowsWithColumnDefinition(
+			NewColumn("id").OfType("INT", int8(0)),
+			NewColumn("created").OfType("DATE", time.Time{}),
+		)
+	}
+
+	cases := []struct {
+		csv      string
+		opts     []CSVOption
+		expected string
+	}{
+		{"1,2026-10-16\nx,2026-10-16", nil, `row 2, column 1 ("id"): parsing "x": invalid syntax`},
+		{"300,2026-10-16", nil, `row 1, column 1 ("id"): parsing "300": value out of range`},
+		{"1,yesterday", nil, `row 1, column 2 ("created"): "yesterday" does not match the time layouts`},
+		{"1,2026-10-16,3", nil, "row 1: expected 2 columns, but got 3"},
+		{"id,updated\n1,2026-10-16", []CSVOption{CSVHeader()}, `CSV header column 2 ("updated") is not defined`},
+		{`1,"2026`, nil, "reading CSV: "},
+	}
+
+	for i, c := range cases {
+		rows := define()
+		_, err := rows.FromCSV(strings.NewReader(c.csv), c.opts...)
+		if err == nil || !strings.HasPrefix(err.Error(), c.expected) {
+			t.Errorf("expected error %q at %d case, but got: %v", c.expected, i, err)
+		}
+		if len(rows.rows) != 0 {
+			t.Errorf("expected no rows to be added at %d case, but got %v", i, rows.rows)
+		}
+	}
+}
+
+func TestRowsFromCSVQuery(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rs, err := NewRowsWithColumnDefinition(
+		NewColumn("id").OfType("INT", int64(0)),
+		NewColumn("active").OfType("BOOL", sql.NullBool{}),
+	).FromCSV(strings.NewReader("1,true\n2,NULL"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	mock.ExpectQuery("SELECT").WillReturnRows(rs)
+
+	rows, err := db.Query("SELECT id, active FROM users")
+	if err != nil {
+		t.Fatalf("error '%s' was not expected, while querying rows", err)
+	}
+	defer rows.Close()
+
+	var actives []sql.NullBool
+	for rows.Next() {
+		var id int
+		var active sql.NullBool
+		if err := rows.Scan(&id, &active); err != nil {
+			t.Fatalf("unexpected scan error: %s", err)
+		}
+		actives = append(actives, active)
+	}
+	if expected := []sql.NullBool{{Bool: true, Valid: true}, {}}; !reflect.DeepEqual(actives, expected) {
+		t.Errorf("expected %v, but got %v", expected, actives)
+	}
+}
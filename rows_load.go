@@ -0,0 +1,249 @@
+package sqlmock
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// FromCSVFile reads the rows from the CSV file at path,
+// see FromCSV. Files with the .tsv extension are read
+// as tab separated.
+func (r *Rows) FromCSVFile(path string, opts ...CSVOption) (*Rows, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return r, err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".tsv") {
+		opts = append([]CSVOption{CSVDelimiter('\t')}, opts...)
+	}
+	if _, err := r.FromCSV(f, opts...); err != nil {
+		return r, fmt.Errorf("%s: %s", path, err)
+	}
+	return r, nil
+}
+
+// FromJSON reads the rows from a JSON array of objects, keyed by the
+// column names, or a JSON array of arrays of the column values, and
+// returns the same instance to perform subsequent actions.
+//
+// If the rows have no columns, they are the keys of the first object
+// in their order, or the first array, which must hold the names. A key
+// missing in an object is NULL. The values are typed by the column
+// definitions or column parsers, as in FromCSV, otherwise numbers are
+// int64 or float64, objects and arrays are their JSON []byte. Of the
+// options, CSVColumns, CSVParser, CSVNull and CSVTimeLayouts apply.
+//
+// If the rows could not be read, it returns an error with the
+// row and column, and no rows are added.
+func (r *Rows) FromJSON(rd io.Reader, opts ...CSVOption) (*Rows, error) {
+	cfg := newCSVConfig(opts)
+	if cfg.columns != nil {
+		r.setColumns(cfg.columns)
+	}
+
+	var items []json.RawMessage
+	if err := json.NewDecoder(rd).Decode(&items); err != nil {
+		return r, fmt.Errorf("reading JSON: %s", err)
+	}
+
+	cols := r.cols
+	var records [][]json.RawMessage
+	for n, item := range items {
+		var record []json.RawMessage
+		switch item = bytes.TrimSpace(item); {
+		case len(item) > 0 && item[0] == '{':
+			keys, values, err := jsonObject(item)
+			if err != nil {
+				return r, fmt.Errorf("row %d: %s", n+1, err)
+			}
+			if len(cols) == 0 && n == 0 {
+				cols = keys
+			}
+			if record, err = jsonRecord(cols, keys, values); err != nil {
+				return r, fmt.Errorf("row %d: %s", n+1, err)
+			}
+		case len(item) > 0 && item[0] == '[':
+			if err := json.Unmarshal(item, &record); err != nil {
+				return r, fmt.Errorf("row %d: %s", n+1, err)
+			}
+			if len(cols) == 0 && n == 0 {
+				if err := json.Unmarshal(item, &cols); err != nil {
+					return r, fmt.Errorf("row 1: expected the column names: %s", err)
+				}
+				continue
+			}
+			if len(record) != len(cols) {
+				return r, fmt.Errorf("row %d: expected %d columns, but got %d", n+1, len(cols), len(record))
+			}
+		default:
+			return r, fmt.Errorf("row %d: expected an object or an array, but got %s", n+1, item)
+		}
+		records = append(records, record)
+	}
+
+	parsers := make([]func(string) (driver.Value, error), len(cols))
+	for i, col := range cols {
+		if p, ok := cfg.parsers[col]; ok {
+			parsers[i] = p
+		} else if r.def != nil && i < len(r.def) && r.def[i].ScanType() != nil {
+			parsers[i] = cfg.typedParser(r.def[i].ScanType())
+		}
+	}
+
+	rows := make([][]driver.Value, len(records))
+	for n, record := range records {
+		rows[n] = make([]driver.Value, len(cols))
+		for i, raw := range record {
+			v, err := jsonValue(raw, parsers[i], cfg)
+			if err != nil {
+				return r, fmt.Errorf("row %d, column %d (%q): %s", n+1, i+1, cols[i], err)
+			}
+			rows[n][i] = v
+		}
+	}
+
+	r.cols = cols
+	r.rows = append(r.rows, rows...)
+	return r, nil
+}
+
+// jsonObject returns the keys of the object in their order and its values
+func jsonObject(item json.RawMessage) ([]string, map[string]json.RawMessage, error) {
+	values := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(item, &values); err != nil {
+		return nil, nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(item))
+	dec.Token() // the opening brace
+	var keys []string
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, key.(string))
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return nil, nil, err
+		}
+	}
+	return keys, values, nil
+}
+
+// jsonRecord returns the values of the object in the column order
+func jsonRecord(cols, keys []string, values map[string]json.RawMessage) ([]json.RawMessage, error) {
+	known := make(map[string]bool, len(cols))
+	for _, col := range cols {
+		known[col] = true
+	}
+	for _, key := range keys {
+		if !known[key] {
+			return nil, fmt.Errorf("unknown column %q", key)
+		}
+	}
+
+	record := make([]json.RawMessage, len(cols))
+	for i, col := range cols {
+		record[i] = values[col]
+	}
+	return record, nil
+}
+
+// jsonValue converts the JSON value, a missing value is NULL
+func jsonValue(raw json.RawMessage, parser func(string) (driver.Value, error), cfg *csvConfig) (driver.Value, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var text string
+	switch raw[0] {
+	case '"':
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil, err
+		}
+		if parser == nil {
+			return text, nil
+		}
+		if cfg.isNull(text) {
+			return nil, nil
+		}
+	case '{', '[':
+		return []byte(raw), nil
+	default:
+		text = string(raw)
+		if parser != nil {
+			break
+		}
+		switch text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return n, nil
+		}
+		return strconv.ParseFloat(text, 64)
+	}
+	return parser(text)
+}
+
+// LoadRows reads the rows from the file at path, which is read by its
+// extension as .csv, .tsv or .json, see FromCSV and FromJSON. The CSV
+// and TSV files must start with a header row naming the columns.
+// YAML files are not supported, since parsing them would require a
+// third party dependency, they may be converted to JSON instead.
+func LoadRows(path string, opts ...CSVOption) (*Rows, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return loadRows(f, path, opts)
+}
+
+// MustLoadRows reads the rows like LoadRows,
+// but panics if the rows cannot be read.
+//
+//	mock.ExpectQuery("SELECT (.+) FROM users").
+//		WillReturnRows(sqlmock.MustLoadRows("testdata/users.json"))
+func MustLoadRows(path string, opts ...CSVOption) *Rows {
+	rows, err := LoadRows(path, opts...)
+	if err != nil {
+		panic(fmt.Sprintf("MustLoadRows: %s", err))
+	}
+	return rows
+}
+
+// loadRows reads the rows from the file by its extension
+func loadRows(rd io.Reader, path string, opts []CSVOption) (*Rows, error) {
+	rows := NewRows(nil)
+	var err error
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		_, err = rows.FromCSV(rd, append([]CSVOption{CSVHeader()}, opts...)...)
+	case ".tsv":
+		_, err = rows.FromTSV(rd, append([]CSVOption{CSVHeader()}, opts...)...)
+	case ".json":
+		_, err = rows.FromJSON(rd, opts...)
+	case ".yaml", ".yml":
+		err = fmt.Errorf("YAML rows files are not supported, convert %q to JSON", filepath.Base(path))
+	default:
+		err = fmt.Errorf("unsupported rows file extension %q, expected .csv, .tsv or .json", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,17 @@
+//go:build go1.16
+// +build go1.16
+
+package sqlmock
+
+import "io/fs"
+
+// FromFS reads the rows from the file at path in the file system, for
+// example an embed.FS of the testdata directory, see LoadRows.
+func FromFS(fsys fs.FS, path string, opts ...CSVOption) (*Rows, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return loadRows(f, path, opts)
+}
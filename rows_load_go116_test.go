@@ -0,0 +1,35 @@
+//go:build go1.16
+// +build go1.16
+
+package sqlmock
+
+import (
+	"database/sql/driver"
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRowsFromFS(t *testing.T) {
+	rows, err := FromFS(os.DirFS("testdata"), "users.tsv")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := []string{"id", "name"}; !reflect.DeepEqual(rows.cols, expected) {
+		t.Errorf("expected columns %v, but got %v", expected, rows.cols)
+	}
+
+	fsys := fstest.MapFS{"fixtures/ids.json": {Data: []byte(`[{"id": 1}, {"id": 2}]`)}}
+	rows, err = FromFS(fsys, "fixtures/ids.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := [][]driver.Value{{int64(1)}, {int64(2)}}; !reflect.DeepEqual(rows.rows, expected) {
+		t.Errorf("expected rows %v, but got %v", expected, rows.rows)
+	}
+
+	if _, err := FromFS(fsys, "fixtures/missing.json"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
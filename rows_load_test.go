@@ -0,0 +1,175 @@
+package sqlmock
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRowsFromJSONObjects(t *testing.T) {
+	rows, err := LoadRows("testdata/users.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if expected := []string{"id", "name", "score", "active", "created", "tags"}; !reflect.DeepEqual(rows.cols, expected) {
+		t.Errorf("expected columns %v, but got %v", expected, rows.cols)
+	}
+	expected := [][]driver.Value{
+		{int64(1), "john", 1.5, true, "2026-10-16T10:00:00Z", []byte(`["a", "b"]`)},
+		{int64(2), "jane", nil, false, "2026-10-16", nil},
+	}
+	if !reflect.DeepEqual(rows.rows, expected) {
+		t.Errorf("expected rows %v, but got %v", expected, rows.rows)
+	}
+}
+
+func TestRowsFromJSONWithColumns(t *testing.T) {
+	rows := MustLoadRows("testdata/users.json", CSVColumns(
+		NewColumn("id").OfType("INT", int64(0)),
+		NewColumn("created").OfType("TIMESTAMP", time.Time{}),
+		NewColumn("name").OfType("VARCHAR", ""),
+		NewColumn("score").OfType("DOUBLE", 0.0),
+		NewColumn("active").OfType("BOOL", false),
+		NewColumn("tags").OfType("JSON", []byte(nil)),
+	))
+
+	expected := [][]driver.Value{
+		{int64(1), time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC), "john", 1.5, true, []byte(`["a", "b"]`)},
+		{int64(2), time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), "jane", nil, false, nil},
+	}
+	if !reflect.DeepEqual(rows.rows, expected) {
+		t.Errorf("expected rows %v, but got %v", expected, rows.rows)
+	}
+}
+
+func TestRowsFromJSONArrays(t *testing.T) {
+	rows, err := NewRows(nil).FromJSON(strings.NewReader(`[["id", "title"], [5, "hello world"], [6, null]]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := []string{"id", "title"}; !reflect.DeepEqual(rows.cols, expected) {
+		t.Errorf("expected columns %v, but got %v", expected, rows.cols)
+	}
+	if expected := [][]driver.Value{{int64(5), "hello world"}, {int64(6), nil}}; !reflect.DeepEqual(rows.rows, expected) {
+		t.Errorf("expected rows %v, but got %v", expected, rows.rows)
+	}
+
+	rows, err = NewRows([]string{"id"}).FromJSON(strings.NewReader(`[[1], [2]]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := [][]driver.Value{{int64(1)}, {int64(2)}}; !reflect.DeepEqual(rows.rows, expected) {
+		t.Errorf("expected rows %v, but got %v", expected, rows.rows)
+	}
+}
+
+func TestRowsFromJSONErrors(t *testing.T) {
+	cases := []struct {
+		json     string
+		expected string
+	}{
+		{`{"id": 1}`, "reading JSON: "},
+		{`[{"id": 1}, {"id": 2, "name": "jane"}]`, `row 2: unknown column "name"`},
+		{`[["a", "b"], [3]]`, "row 2: expected 2 columns, but got 1"},
+		{`[1]`, "row 1: expected an object or an array, but got 1"},
+		{`[[1]]`, "row 1: expected the column names: "},
+	}
+
+	for i, c := range cases {
+		rows := NewRows(nil)
+		_, err := rows.FromJSON(strings.NewReader(c.json))
+		if err == nil || !strings.HasPrefix(err.Error(), c.expected) {
+			t.Errorf("expected error %q at %d case, but got: %v", c.expected, i, err)
+		}
+		if len(rows.rows) != 0 {
+			t.Errorf("expected no rows to be added at %d case, but got %v", i, rows.rows)
+		}
+	}
+
+	_, err := NewRows(nil).FromJSON(strings.NewReader(`[{"id": "x"}]`), CSVColumns(NewColumn("id").OfType("INT", 0)))
+	if expected := `row 1, column 1 ("id"): parsing "x": invalid syntax`; err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, but got: %v", expected, err)
+	}
+}
+
+func TestLoadRowsFromCSVFiles(t *testing.T) {
+	rows, err := LoadRows("testdata/users.csv", CSVColumns(
+		NewColumn("id").OfType("INT", int64(0)),
+		NewColumn("name").OfType("VARCHAR", ""),
+		NewColumn("created").OfType("TIMESTAMP", time.Time{}),
+	))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := [][]driver.Value{
+		{int64(1), "john", time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)},
+		{int64(2), "jane", nil},
+	}
+	if !reflect.DeepEqual(rows.rows, expected) {
+		t.Errorf("expected rows %v, but got %v", expected, rows.rows)
+	}
+
+	rows, err = NewRows([]string{"id", "name"}).FromCSVFile("testdata/users.tsv", CSVHeader())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := [][]driver.Value{{[]byte("1"), []byte("john")}, {[]byte("2"), []byte("jane doe")}}; !reflect.DeepEqual(rows.rows, expected) {
+		t.Errorf("expected rows %v, but got %v", expected, rows.rows)
+	}
+}
+
+func TestLoadRowsErrors(t *testing.T) {
+	if _, err := LoadRows("testdata/missing.json"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+	if _, err := LoadRows("rows.go"); err == nil || !strings.Contains(err.Error(), `unsupported rows file extension ".go"`) {
+		t.Errorf("expected an unsupported extension error, but got: %v", err)
+	}
+	if _, err := loadRows(strings.NewReader("- id: 1"), "testdata/users.yml", nil); err == nil || !strings.Contains(err.Error(), "YAML rows files are not supported") {
+		t.Errorf("expected a YAML not supported error, but got: %v", err)
+	}
+
+	defer func() {
+		if e := recover(); e == nil {
+			t.Error("expected MustLoadRows to panic")
+		}
+	}()
+	MustLoadRows("testdata/missing.csv")
+}
+
+func TestLoadRowsQuery(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(MustLoadRows("testdata/users.json"))
+
+	rows, err := db.Query("SELECT id, name, score, active, created, tags FROM users")
+	if err != nil {
+		t.Fatalf("error '%s' was not expected, while querying rows", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var id int
+		var name string
+		var score *float64
+		var active bool
+		var created string
+		var tags []byte
+		if err := rows.Scan(&id, &name, &score, &active, &created, &tags); err != nil {
+			t.Fatalf("unexpected scan error: %s", err)
+		}
+		names = append(names, name)
+	}
+	if expected := []string{"john", "jane"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, but got %v", expected, names)
+	}
+}
@@ -0,0 +1,189 @@
+package sqlmock
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// StructOption configures how NewRowsFromStructs
+// maps the struct fields to columns
+type StructOption func(*structMapping)
+
+type structMapping struct {
+	tags      []string
+	converter driver.ValueConverter
+}
+
+// StructTag makes NewRowsFromStructs read the column names from the
+// given struct tags, in the order of preference, instead of the
+// default db and sql tags.
+func StructTag(tags ...string) StructOption {
+	return func(m *structMapping) {
+		m.tags = tags
+	}
+}
+
+// StructConverter makes NewRowsFromStructs convert the field values
+// with the given converter, as Sqlmock.NewRows does.
+func StructConverter(converter driver.ValueConverter) StructOption {
+	return func(m *structMapping) {
+		m.converter = converter
+	}
+}
+
+// structField is a column mapped from a possibly embedded struct field
+type structField struct {
+	column string
+	index  []int
+}
+
+// NewRowsFromStructs allows Rows to be created from a slice
+// of structs or pointers to structs, a row for each item.
+//
+// The columns are the exported fields, named by their db or sql tag,
+// like sqlstruct does, or by the lower cased field name if the field
+// is not tagged. Fields tagged with "-" are left out and the fields
+// of embedded structs are mapped as if they were declared in the
+// outer struct. Nil pointers become NULL, other values are converted
+// as by Rows.AddRow, so that sql.NullString, driver.Valuer and
+// pointer fields are supported.
+//
+// It panics if items is not a slice of structs.
+// Use Sqlmock.NewRowsFromStructs instead if using a custom converter
+func NewRowsFromStructs(items interface{}, opts ...StructOption) *Rows {
+	m := &structMapping{
+		tags:      []string{"db", "sql"},
+		converter: driver.DefaultParameterConverter,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		panic(fmt.Sprintf("NewRowsFromStructs: expected a slice of structs, but got %T", items))
+	}
+	typ := v.Type().Elem()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("NewRowsFromStructs: expected a slice of structs, but got %T", items))
+	}
+
+	fields := m.fields(typ, nil)
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = f.column
+	}
+
+	rows := NewRows(columns)
+	rows.converter = m.converter
+	for i := 0; i < v.Len(); i++ {
+		item := reflect.Indirect(v.Index(i))
+		if !item.IsValid() {
+			panic(fmt.Sprintf("NewRowsFromStructs: item #%d is a nil pointer", i))
+		}
+		values := make([]driver.Value, len(fields))
+		for j, f := range fields {
+			values[j] = fieldValue(item, f.index)
+		}
+		rows.AddRow(values...)
+	}
+	return rows
+}
+
+// NewRowsFromStructs allows Rows to be created from a slice
+// of structs, converting the values with the mock converter.
+// See the package level NewRowsFromStructs.
+func (c *sqlmock) NewRowsFromStructs(items interface{}, opts ...StructOption) *Rows {
+	return NewRowsFromStructs(items, append([]StructOption{StructConverter(c.converter)}, opts...)...)
+}
+
+// fields returns the columns mapped from the struct type
+func (m *structMapping) fields(typ reflect.Type, index []int) []structField {
+	var fields []structField
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		name, tagged := m.column(f)
+		if name == "-" {
+			continue
+		}
+
+		idx := make([]int, len(index)+1)
+		copy(idx, index)
+		idx[len(index)] = i
+
+		if f.Anonymous && !tagged {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				if f.PkgPath != "" {
+					continue // fields of an unexported pointer are not accessible
+				}
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !isScalarStruct(f.Type) {
+				fields = append(fields, m.fields(ft, idx)...)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue // unexported
+		}
+		fields = append(fields, structField{column: name, index: idx})
+	}
+	return fields
+}
+
+// column returns the column name of the field, from the
+// first tag present or the lower cased field name
+func (m *structMapping) column(f reflect.StructField) (string, bool) {
+	for _, tag := range m.tags {
+		if name, ok := f.Tag.Lookup(tag); ok {
+			if name = strings.Split(name, ",")[0]; name != "" {
+				return name, true
+			}
+		}
+	}
+	return strings.ToLower(f.Name), false
+}
+
+// isScalarStruct reports whether the struct type is a single
+// value, which must not be flattened into columns
+func isScalarStruct(typ reflect.Type) bool {
+	valuer := reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	if typ.Implements(valuer) || reflect.PtrTo(typ).Implements(valuer) {
+		return true
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ == reflect.TypeOf(time.Time{})
+}
+
+// fieldValue returns the value of the field at index, nil
+// if it is reached through a nil embedded pointer or is a
+// nil pointer itself
+func fieldValue(v reflect.Value, index []int) driver.Value {
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return nil
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		if _, ok := v.Interface().(driver.Valuer); !ok {
+			v = v.Elem()
+		}
+	}
+	return v.Interface()
+}
@@ -0,0 +1,130 @@
+package sqlmock
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type structRowsBase struct {
+	ID      int       `db:"id"`
+	Created time.Time `db:"created_at"`
+}
+
+type structRowsAudit struct {
+	Editor string `sql:"editor"`
+}
+
+type structRowsUser struct {
+	structRowsBase
+	*structRowsAudit `db:"-"`
+	*Extra
+	Name     string         `db:"name,omitempty"`
+	Email    sql.NullString `sql:"email"`
+	Nickname *string
+	Age      int `json:"years"`
+	password string
+	Skipped  bool `db:"-"`
+}
+
+type Extra struct {
+	Score float64 `db:"score"`
+}
+
+func TestNewRowsFromStructs(t *testing.T) {
+	created := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	nick := "jd"
+	users := []*structRowsUser{
+		{
+			structRowsBase: structRowsBase{ID: 1, Created: created},
+			Extra:          &Extra{Score: 1.5},
+			Name:           "john",
+			Email:          sql.NullString{String: "john@example.com", Valid: true},
+			Nickname:       &nick,
+			Age:            30,
+		},
+		{
+			structRowsBase: structRowsBase{ID: 2, Created: created},
+			Name:           "jane",
+		},
+	}
+
+	rows := NewRowsFromStructs(users)
+
+	expectedCols := []string{"id", "created_at", "score", "name", "email", "nickname", "age"}
+	if !reflect.DeepEqual(rows.cols, expectedCols) {
+		t.Errorf("expected columns %v, but got %v", expectedCols, rows.cols)
+	}
+
+	expectedRows := [][]driver.Value{
+		{int64(1), created, 1.5, "john", "john@example.com", "jd", int64(30)},
+		{int64(2), created, nil, "jane", nil, nil, int64(0)},
+	}
+	if !reflect.DeepEqual(rows.rows, expectedRows) {
+		t.Errorf("expected rows %v, but got %v", expectedRows, rows.rows)
+	}
+}
+
+func TestNewRowsFromStructsWithTag(t *testing.T) {
+	type item struct {
+		ID   int    `json:"item_id" db:"id"`
+		Name string `json:"item_name"`
+	}
+
+	rows := NewRowsFromStructs([]item{{ID: 1, Name: "one"}}, StructTag("json"))
+	if expected := []string{"item_id", "item_name"}; !reflect.DeepEqual(rows.cols, expected) {
+		t.Errorf("expected columns %v, but got %v", expected, rows.cols)
+	}
+}
+
+func TestNewRowsFromStructsQuery(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New(ValueConverterOption(CustomConverter{}))
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	type article struct {
+		ID    int    `sql:"id"`
+		Title string `sql:"title"`
+	}
+
+	rs := mock.NewRowsFromStructs([]article{{5, "hello world"}})
+	if _, ok := rs.converter.(CustomConverter); !ok {
+		t.Errorf("expected the mock converter to be used, but got %T", rs.converter)
+	}
+	mock.ExpectQuery("SELECT (.+) FROM articles").WillReturnRows(rs)
+
+	var id int
+	var title string
+	if err := db.QueryRow("SELECT id, title FROM articles").Scan(&id, &title); err != nil {
+		t.Fatalf("error '%s' was not expected, while querying a row", err)
+	}
+	if id != 5 || title != "hello world" {
+		t.Errorf("expected article 5 hello world, but got %d %s", id, title)
+	}
+}
+
+func TestNewRowsFromStructsInvalid(t *testing.T) {
+	for name, items := range map[string]interface{}{
+		"not a slice":   structRowsBase{},
+		"not structs":   []int{1},
+		"nil item":      []*structRowsBase{nil},
+		"unconvertible": []struct{ Ch chan int }{{}},
+	} {
+		func() {
+			defer func() {
+				if e := recover(); e == nil {
+					t.Errorf("%s: expected a panic", name)
+				} else if msg, ok := e.(string); ok && !strings.HasPrefix(msg, "NewRowsFromStructs") {
+					t.Errorf("%s: unexpected panic: %s", name, msg)
+				}
+			}()
+			NewRowsFromStructs(items)
+		}()
+	}
+}
@@ -0,0 +1,244 @@
+package sqlmock
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// savepointAction is the action of a savepoint statement
+type savepointAction int
+
+const (
+	savepointCreate savepointAction = iota
+	savepointRollback
+	savepointRelease
+)
+
+// savepointStmt is a savepoint statement recognized in an exec query
+type savepointStmt struct {
+	action savepointAction
+	name   string
+}
+
+func (s savepointStmt) String() string {
+	switch s.action {
+	case savepointRollback:
+		return "ROLLBACK TO SAVEPOINT " + s.name
+	case savepointRelease:
+		return "RELEASE SAVEPOINT " + s.name
+	}
+	return "SAVEPOINT " + s.name
+}
+
+// parseSavepoint recognizes the savepoint statements of the common
+// dialects:
+//
+//	SAVEPOINT name
+//	ROLLBACK [WORK | TRANSACTION] TO [SAVEPOINT] name
+//	RELEASE [SAVEPOINT] name
+//	SAVE TRAN[SACTION] name        (SQL Server)
+//	ROLLBACK TRAN[SACTION] name    (SQL Server)
+//
+// The name may be quoted with double quotes, backticks or brackets.
+func parseSavepoint(query string) (savepointStmt, bool) {
+	words := strings.Fields(strings.TrimRight(strings.TrimSpace(query), ";"))
+	keywords := make([]string, len(words))
+	for i, w := range words {
+		keywords[i] = strings.ToUpper(w)
+	}
+
+	// at reports whether the word at i is one of the given keywords
+	at := func(i int, expected ...string) bool {
+		for _, kw := range expected {
+			if i < len(keywords) && keywords[i] == kw {
+				return true
+			}
+		}
+		return false
+	}
+	// named returns the statement, if the word at i is the last one
+	named := func(action savepointAction, i int) (savepointStmt, bool) {
+		if i != len(words)-1 {
+			return savepointStmt{}, false
+		}
+		return savepointStmt{action: action, name: unquoteIdent(words[i])}, true
+	}
+
+	switch {
+	case at(0, "SAVEPOINT"):
+		return named(savepointCreate, 1)
+	case at(0, "SAVE") && at(1, "TRAN", "TRANSACTION"):
+		return named(savepointCreate, 2)
+	case at(0, "RELEASE"):
+		if at(1, "SAVEPOINT") {
+			return named(savepointRelease, 2)
+		}
+		return named(savepointRelease, 1)
+	case at(0, "ROLLBACK"):
+		i := 1
+		if at(i, "WORK", "TRAN", "TRANSACTION") {
+			i++
+		}
+		if at(i, "TO") {
+			if at(i+1, "SAVEPOINT") {
+				return named(savepointRollback, i+2)
+			}
+			return named(savepointRollback, i+1)
+		}
+		if at(1, "TRAN", "TRANSACTION") {
+			return named(savepointRollback, 2)
+		}
+	}
+	return savepointStmt{}, false
+}
+
+// unquoteIdent removes the quotes around an identifier
+func unquoteIdent(name string) string {
+	if len(name) > 1 {
+		switch {
+		case name[0] == '"' && name[len(name)-1] == '"',
+			name[0] == '`' && name[len(name)-1] == '`',
+			name[0] == '[' && name[len(name)-1] == ']':
+			return name[1 : len(name)-1]
+		}
+	}
+	return name
+}
+
+// applySavepoint tracks the savepoints created in the transaction, it
+// returns an error if the statement refers to an unknown savepoint
+func (tx *transaction) applySavepoint(stmt savepointStmt) error {
+	if stmt.action == savepointCreate {
+		tx.savepoints = append(tx.savepoints, stmt.name)
+		return nil
+	}
+
+	for i := len(tx.savepoints) - 1; i >= 0; i-- {
+		if !strings.EqualFold(tx.savepoints[i], stmt.name) {
+			continue
+		}
+		if stmt.action == savepointRollback {
+			// the savepoint remains, the ones created after it are destroyed
+			tx.savepoints = tx.savepoints[:i+1]
+		} else {
+			tx.savepoints = tx.savepoints[:i]
+		}
+		return nil
+	}
+	return fmt.Errorf("%s, savepoint '%s' does not exist in the transaction", stmt, stmt.name)
+}
+
+// execSavepoint runs the query as a savepoint statement, if it is one and
+// a savepoint is expected, otherwise the query is matched against the exec
+// expectations and handled is false
+func (c *conn) execSavepoint(query string) (_ driver.Result, handled bool, err error) {
+	stmt, ok := parseSavepoint(query)
+	if !ok {
+		return nil, false, nil
+	}
+
+	ex, err := c.savepoint(c, stmt)
+	if ex == nil && err == nil {
+		return nil, false, nil
+	}
+	defer c.record(CallExec, query, nil, ex, &err)
+	if ex == nil {
+		return nil, true, c.reject(err)
+	}
+	if err != nil {
+		return nil, true, err
+	}
+	return NewResult(0, 0), true, nil
+}
+
+// savepoint matches a savepoint statement, it returns neither an
+// expectation nor an error if no savepoint expectation is pending
+func (c *sqlmock) savepoint(cn *conn, stmt savepointStmt) (*ExpectedSavepoint, error) {
+	var expected, scoped *ExpectedSavepoint
+	for _, next := range c.expected {
+		next.Lock()
+		if next.exhausted() {
+			next.Unlock()
+			continue
+		}
+
+		sp, ok := next.(*ExpectedSavepoint)
+		if ok && c.ordered {
+			expected = sp
+			break
+		}
+		if ok && sp.matches(stmt) {
+			if sp.check(cn) == nil {
+				expected = sp
+				break
+			}
+			if scoped == nil {
+				scoped = sp
+			}
+		}
+
+		satisfied := next.fulfilled()
+		next.Unlock()
+		if c.ordered && !satisfied {
+			break
+		}
+	}
+
+	if expected == nil && scoped != nil {
+		// the savepoint is expected, but in another scope
+		scoped.Lock()
+		expected = scoped
+	}
+
+	if expected == nil {
+		return nil, nil
+	}
+	defer expected.Unlock()
+
+	if !expected.matches(stmt) {
+		return nil, fmt.Errorf("call to %s, was not expected, next expectation is: %s", stmt, expected)
+	}
+	if err := expected.check(cn); err != nil {
+		return nil, fmt.Errorf("%s, %s", stmt, err)
+	}
+	if expected.err == nil && cn.tx != nil {
+		if err := cn.tx.applySavepoint(stmt); err != nil {
+			return nil, err
+		}
+	}
+
+	expected.triggered++
+	c.passBefore(expected)
+	return expected, expected.err
+}
+
+// enclosingBegin returns the last Begin expected, if any
+func (c *sqlmock) enclosingBegin() *ExpectedBegin {
+	for i := len(c.expected) - 1; i >= 0; i-- {
+		if begin, ok := c.expected[i].(*ExpectedBegin); ok {
+			return begin
+		}
+	}
+	return nil
+}
+
+func (c *sqlmock) expectSavepoint(action savepointAction, name string) *ExpectedSavepoint {
+	e := &ExpectedSavepoint{savepointStmt: savepointStmt{action: action, name: name}}
+	e.inTx = true
+	e.begin = c.enclosingBegin()
+	c.expected = append(c.expected, e)
+	return e
+}
+
+func (c *sqlmock) ExpectSavepoint(name string) *ExpectedSavepoint {
+	return c.expectSavepoint(savepointCreate, name)
+}
+
+func (c *sqlmock) ExpectRollbackToSavepoint(name string) *ExpectedSavepoint {
+	return c.expectSavepoint(savepointRollback, name)
+}
+
+func (c *sqlmock) ExpectReleaseSavepoint(name string) *ExpectedSavepoint {
+	return c.expectSavepoint(savepointRelease, name)
+}
@@ -0,0 +1,201 @@
+package sqlmock
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSavepoint(t *testing.T) {
+	cases := []struct {
+		query  string
+		ok     bool
+		action savepointAction
+		name   string
+	}{
+		{"SAVEPOINT sp1", true, savepointCreate, "sp1"},
+		{"savepoint \"sp1\";", true, savepointCreate, "sp1"},
+		{"SAVE TRANSACTION [sp1]", true, savepointCreate, "sp1"},
+		{"SAVE TRAN sp1", true, savepointCreate, "sp1"},
+		{"ROLLBACK TO SAVEPOINT sp1", true, savepointRollback, "sp1"},
+		{"rollback to `sp1`", true, savepointRollback, "sp1"},
+		{"ROLLBACK WORK TO SAVEPOINT sp1", true, savepointRollback, "sp1"},
+		{"ROLLBACK TRANSACTION TO SAVEPOINT sp1", true, savepointRollback, "sp1"},
+		{"ROLLBACK TRANSACTION sp1", true, savepointRollback, "sp1"},
+		{"RELEASE SAVEPOINT sp1", true, savepointRelease, "sp1"},
+		{"release sp1", true, savepointRelease, "sp1"},
+		{"ROLLBACK", false, 0, ""},
+		{"ROLLBACK TRANSACTION", false, 0, ""},
+		{"ROLLBACK WORK", false, 0, ""},
+		{"SELECT savepoint FROM logs", false, 0, ""},
+		{"SAVEPOINT", false, 0, ""},
+	}
+
+	for _, c := range cases {
+		stmt, ok := parseSavepoint(c.query)
+		if ok != c.ok {
+			t.Errorf("query %q: expected recognized to be %t, but got %t", c.query, c.ok, ok)
+			continue
+		}
+		if ok && (stmt.action != c.action || stmt.name != c.name) {
+			t.Errorf("query %q: expected %s, but got %s", c.query, savepointStmt{c.action, c.name}, stmt)
+		}
+	}
+}
+
+func TestSavepointsInTransaction(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectSavepoint("sp1")
+	mock.ExpectExec("INSERT INTO users").WillReturnResult(NewResult(1, 1))
+	mock.ExpectRollbackToSavepoint("sp1")
+	mock.ExpectReleaseSavepoint("sp1")
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+	for _, query := range []string{"SAVEPOINT sp1", "INSERT INTO users(name) VALUES ('john')", "ROLLBACK TO sp1", "RELEASE SAVEPOINT sp1"} {
+		if _, err := tx.Exec(query); err != nil {
+			t.Fatalf("unexpected error on %q: %s", query, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("unexpected error on commit: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRollbackToSavepointAfterCommit(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	mock.ExpectBegin()
+	mock.ExpectSavepoint("sp1")
+	mock.ExpectCommit()
+	mock.ExpectRollbackToSavepoint("sp1")
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+	if _, err := tx.Exec("SAVEPOINT sp1"); err != nil {
+		t.Fatalf("unexpected error on savepoint: %s", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("unexpected error on commit: %s", err)
+	}
+
+	_, err = db.Exec("ROLLBACK TO SAVEPOINT sp1")
+	if err == nil {
+		t.Fatal("expected an error, since the transaction was already committed")
+	}
+	if !strings.Contains(err.Error(), "the transaction was already committed") {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestReleaseUnknownSavepoint(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	begin := mock.ExpectBegin()
+	begin.ExpectSavepoint("sp1")
+	begin.ExpectReleaseSavepoint("sp2")
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SAVEPOINT sp1"); err != nil {
+		t.Fatalf("unexpected error on savepoint: %s", err)
+	}
+	_, err = tx.Exec("RELEASE SAVEPOINT sp2")
+	if err == nil {
+		t.Fatal("expected an error, since the savepoint was not created")
+	}
+	if !strings.Contains(err.Error(), "savepoint 'sp2' does not exist in the transaction") {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestSavepointExpectedAsExec(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT sp1").WillReturnResult(NewResult(0, 0))
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SAVEPOINT sp1"); err != nil {
+		t.Fatalf("unexpected error on savepoint: %s", err)
+	}
+}
+
+func TestUnorderedSavepointsInDistinctTransactions(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.MatchExpectationsInOrder(false)
+	first := mock.ExpectBegin()
+	first.ExpectSavepoint("sp")
+	second := mock.ExpectBegin()
+	second.ExpectSavepoint("sp")
+
+	tx1, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+	tx2, err := db.Begin()
+	if err != nil {
+		t.Fatalf("unexpected error on begin: %s", err)
+	}
+
+	// the second transaction creates its savepoint first
+	if _, err := tx2.Exec("SAVEPOINT sp"); err != nil {
+		t.Errorf("unexpected error on the savepoint of the second transaction: %s", err)
+	}
+	if _, err := tx1.Exec("SAVEPOINT sp"); err != nil {
+		t.Errorf("unexpected error on the savepoint of the first transaction: %s", err)
+	}
+	mock.ExpectRollback().Times(2)
+	tx1.Rollback()
+	tx2.Rollback()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
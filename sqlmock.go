@@ -11,9 +11,13 @@ The driver allows to mock any sql driver method behavior.
 package sqlmock
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
+	"io"
+	"log"
+	"sync"
 	"time"
 )
 
@@ -26,8 +30,22 @@ type SqlmockCommon interface {
 	// to mock database response
 	ExpectClose() *ExpectedClose
 
+	// ExpectConnect expects database/sql to open a new connection.
+	// the *ExpectedConnection allows to mock database response and
+	// to expect statements on that very connection.
+	//
+	// You must enable multiple connections using MultipleConnectionsOption
+	// for this to register any expectations. Otherwise, the returned
+	// connection is not expected and the statements expected on it may
+	// run on any connection.
+	ExpectConnect() *ExpectedConnection
+
 	// ExpectationsWereMet checks whether all queued expectations
 	// were met in order. If any of them was not met - an error is returned.
+	//
+	// The returned error is an *ExpectationsError, which lists every
+	// unmet expectation, every prepared statement or query rows not
+	// closed as expected and every call rejected since it was not expected.
 	ExpectationsWereMet() error
 
 	// ExpectPrepare expects Prepare() to be called with expectedSQL query.
@@ -56,6 +74,22 @@ type SqlmockCommon interface {
 	// the *ExpectedRollback allows to mock database response
 	ExpectRollback() *ExpectedRollback
 
+	// ExpectSavepoint expects a SAVEPOINT statement creating the named
+	// savepoint to be executed in the transaction started by the last
+	// expected Begin, or in any transaction if no Begin was expected.
+	// The statement is recognized in the syntax of the common dialects.
+	ExpectSavepoint(name string) *ExpectedSavepoint
+
+	// ExpectRollbackToSavepoint expects a ROLLBACK TO SAVEPOINT statement
+	// to be executed in the transaction started by the last expected Begin,
+	// the savepoint must have been created in that transaction.
+	ExpectRollbackToSavepoint(name string) *ExpectedSavepoint
+
+	// ExpectReleaseSavepoint expects a RELEASE SAVEPOINT statement
+	// to be executed in the transaction started by the last expected Begin,
+	// the savepoint must have been created in that transaction.
+	ExpectReleaseSavepoint(name string) *ExpectedSavepoint
+
 	// ExpectPing expected *sql.DB.Ping to be called.
 	// the *ExpectedPing allows to mock database response
 	//
@@ -67,6 +101,16 @@ type SqlmockCommon interface {
 	// any expectations.
 	ExpectPing() *ExpectedPing
 
+	// ExpectResetSession expects database/sql to reset the session of
+	// a connection, which it does before reusing a connection from the pool.
+	// the *ExpectedResetSession allows to mock database response
+	//
+	// Session resets only exist in the SQL library in Go 1.10 and above.
+	//
+	// You must enable session resets monitoring using MonitorSessionResetsOption
+	// for this to register any expectations.
+	ExpectResetSession() *ExpectedResetSession
+
 	// MatchExpectationsInOrder gives an option whether to match all
 	// expectations in the order they were set or not.
 	//
@@ -84,22 +128,46 @@ type SqlmockCommon interface {
 	// sql driver.Value slice or from the CSV string and
 	// to be used as sql driver.Rows.
 	NewRows(columns []string) *Rows
+
+	// LoadExpectations adds the expectations described by a JSON
+	// script, validating the whole script before adding any of them.
+	LoadExpectations(rd io.Reader) error
+
+	// NewRowsFromStructs allows Rows to be created from a slice
+	// of structs, the columns are mapped from the struct fields.
+	NewRowsFromStructs(items interface{}, opts ...StructOption) *Rows
 }
 
 type sqlmock struct {
-	ordered      bool
-	dsn          string
-	opened       int
-	drv          *mockDriver
-	converter    driver.ValueConverter
-	queryMatcher QueryMatcher
-	monitorPings bool
+	ordered       bool
+	dsn           string
+	opened        int
+	drv           *mockDriver
+	converter     driver.ValueConverter
+	queryMatcher  QueryMatcher
+	placeholders  PlaceholderStyle
+	monitorPings  bool
+	monitorResets bool
+
+	// whether every connection is expected with ExpectConnect
+	multipleConns bool
+	connections   int // number of connections opened, guarded by drv
+
+	// whether rejected calls are left out of ExpectationsWereMet
+	ignoreUnexpected bool
+	// whether rejected calls are reported right away to the test
+	failOnUnexpected bool
 
 	expected []expectation
+
+	mu           sync.Mutex // guards unexpected, onUnexpected and calls
+	unexpected   []error
+	onUnexpected func(error) // reports rejected calls to the test, set by NewT
+	calls        []Call
 }
 
 func (c *sqlmock) open(options []SqlMockOption) (*sql.DB, Sqlmock, error) {
-	db, err := sql.Open("sqlmock", c.dsn)
+	db, err := c.openDB()
 	if err != nil {
 		return db, c, err
 	}
@@ -116,6 +184,12 @@ func (c *sqlmock) open(options []SqlMockOption) (*sql.DB, Sqlmock, error) {
 		c.queryMatcher = QueryMatcherRegexp
 	}
 
+	if c.multipleConns {
+		// Connections are opened by database/sql only once needed, so
+		// that every one of them could be expected with ExpectConnect.
+		return db, c, nil
+	}
+
 	if c.monitorPings {
 		// We call Ping on the driver shortly to verify startup assertions by
 		// driving internal behaviour of the sql standard library. We don't
@@ -133,15 +207,109 @@ func (c *sqlmock) ExpectClose() *ExpectedClose {
 	return e
 }
 
+func (c *sqlmock) ExpectConnect() *ExpectedConnection {
+	if !c.multipleConns {
+		log.Println("ExpectConnect will have no effect as every connection is the same. Use MultipleConnectionsOption to enable.")
+		return &ExpectedConnection{mock: c, detached: true}
+	}
+	e := &ExpectedConnection{mock: c}
+	c.expected = append(c.expected, e)
+	return e
+}
+
+// dial matches a new connection against the expectations
+func (c *conn) dial(ctx context.Context) (err error) {
+	ex, err := c.connect()
+	defer c.record(CallConnect, "", nil, ex, &err)
+	if ex == nil {
+		return c.reject(err)
+	}
+
+	select {
+	case <-time.After(ex.delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if err != nil {
+		return err
+	}
+
+	ex.Lock()
+	ex.id = c.id
+	ex.Unlock()
+	c.expected = ex
+	return err
+}
+
+func (c *sqlmock) connect() (*ExpectedConnection, error) {
+	var expected *ExpectedConnection
+	var fulfilled int
+	var ok bool
+	for _, next := range c.expected {
+		next.Lock()
+		if next.exhausted() {
+			next.Unlock()
+			fulfilled++
+			continue
+		}
+
+		if expected, ok = next.(*ExpectedConnection); ok {
+			break
+		}
+
+		satisfied := next.fulfilled()
+		next.Unlock()
+		if satisfied {
+			fulfilled++
+			continue
+		}
+		if c.ordered {
+			return nil, fmt.Errorf("call to database Connect, was not expected, next expectation is: %s", next)
+		}
+	}
+
+	if expected == nil {
+		msg := "call to database Connect was not expected"
+		if fulfilled == len(c.expected) {
+			msg = "all expectations were already fulfilled, " + msg
+		}
+		return nil, fmt.Errorf(msg)
+	}
+
+	expected.triggered++
+	c.passBefore(expected)
+	expected.Unlock()
+	return expected, expected.err
+}
+
 func (c *sqlmock) MatchExpectationsInOrder(b bool) {
 	c.ordered = b
 }
 
+// passBefore closes the expectations before the triggered one, when
+// they are matched in order, so that a repeatable expectation cannot
+// be matched again once a following expectation was triggered
+func (c *sqlmock) passBefore(triggered expectation) {
+	if !c.ordered {
+		return
+	}
+	for _, next := range c.expected {
+		if next == triggered {
+			return
+		}
+		next.Lock()
+		next.pass()
+		next.Unlock()
+	}
+}
+
 // Close a mock database driver connection. It may or may not
 // be called depending on the circumstances, but if it is called
-// there must be an *ExpectedClose expectation satisfied.
+// there must be an *ExpectedClose expectation satisfied. Unless
+// any close was expected, an unexpected close is only recorded
+// in the call history and not reported by ExpectationsWereMet.
 // meets http://golang.org/pkg/database/sql/driver/#Conn interface
-func (c *sqlmock) Close() error {
+func (c *conn) Close() (err error) {
 	c.drv.Lock()
 	defer c.drv.Unlock()
 
@@ -150,12 +318,37 @@ func (c *sqlmock) Close() error {
 		delete(c.drv.conns, c.dsn)
 	}
 
+	ex, err := c.close()
+	defer c.record(CallClose, "", nil, ex, &err)
+	if ex == nil {
+		// database/sql closes idle connections on its own, so
+		// an unexpected close is reported only if closes were
+		// expected at all
+		if c.expectsClose() {
+			return c.reject(err)
+		}
+		return err
+	}
+	return err
+}
+
+// expectsClose reports whether any ExpectClose was queued
+func (c *sqlmock) expectsClose() bool {
+	for _, e := range c.expected {
+		if _, ok := e.(*ExpectedClose); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *sqlmock) close() (*ExpectedClose, error) {
 	var expected *ExpectedClose
 	var fulfilled int
 	var ok bool
 	for _, next := range c.expected {
 		next.Lock()
-		if next.fulfilled() {
+		if next.exhausted() {
 			next.Unlock()
 			fulfilled++
 			continue
@@ -165,9 +358,14 @@ func (c *sqlmock) Close() error {
 			break
 		}
 
+		satisfied := next.fulfilled()
 		next.Unlock()
+		if satisfied {
+			fulfilled++
+			continue
+		}
 		if c.ordered {
-			return fmt.Errorf("call to database Close, was not expected, next expectation is: %s", next)
+			return nil, fmt.Errorf("call to database Close, was not expected, next expectation is: %s", next)
 		}
 	}
 
@@ -176,52 +374,100 @@ func (c *sqlmock) Close() error {
 		if fulfilled == len(c.expected) {
 			msg = "all expectations were already fulfilled, " + msg
 		}
-		return fmt.Errorf(msg)
+		return nil, fmt.Errorf(msg)
 	}
 
-	expected.triggered = true
+	expected.triggered++
+	c.passBefore(expected)
 	expected.Unlock()
-	return expected.err
+	return expected, expected.err
 }
 
 func (c *sqlmock) ExpectationsWereMet() error {
+	report := &ExpectationsError{}
 	for _, e := range c.expected {
 		e.Lock()
 		fulfilled := e.fulfilled()
+		calls := e.calls()
 		e.Unlock()
 
 		if !fulfilled {
-			return fmt.Errorf("there is a remaining expectation which was not matched: %s", e)
+			report.unmet(e, calls)
 		}
 
 		// for expected prepared statement check whether it was closed if expected
 		if prep, ok := e.(*ExpectedPrepare); ok {
 			if prep.mustBeClosed && !prep.wasClosed {
-				return fmt.Errorf("expected prepared statement to be closed, but it was not: %s", prep)
+				report.unclosedStatement(prep)
 			}
 		}
 
 		// must check whether all expected queried rows are closed
 		if query, ok := e.(*ExpectedQuery); ok {
 			if query.rowsMustBeClosed && !query.rowsWereClosed {
-				return fmt.Errorf("expected query rows to be closed, but it was not: %s", query)
+				report.unclosedRows(query)
 			}
 		}
 	}
-	return nil
+
+	if !c.ignoreUnexpected {
+		c.mu.Lock()
+		for _, err := range c.unexpected {
+			report.unexpected(err)
+		}
+		c.mu.Unlock()
+	}
+
+	if report.empty() {
+		return nil
+	}
+	return report
+}
+
+// reject records the error of a call, which did not match any
+// expectation, so that ExpectationsWereMet reports it even if
+// the caller ignored the error, unless it is reported to the
+// test right away
+func (c *sqlmock) reject(err error) error {
+	if err == nil {
+		return nil
+	}
+	c.mu.Lock()
+	var report func(error)
+	if c.failOnUnexpected {
+		report = c.onUnexpected
+	}
+	if report == nil {
+		c.unexpected = append(c.unexpected, err)
+	}
+	c.mu.Unlock()
+
+	if report != nil {
+		report(err)
+	}
+	return err
 }
 
 // Begin meets http://golang.org/pkg/database/sql/driver/#Conn interface
-func (c *sqlmock) Begin() (driver.Tx, error) {
+func (c *conn) Begin() (_ driver.Tx, err error) {
 	ex, err := c.begin(driver.TxOptions{})
-	if ex != nil {
-		time.Sleep(ex.delay)
+	defer c.record(CallBegin, "", nil, ex, &err)
+	defer c.invalidate(&err)
+	if ex == nil {
+		return nil, c.reject(err)
 	}
+	time.Sleep(ex.delay)
 	if err != nil {
 		return nil, err
 	}
 
-	return c, nil
+	return c.startTx(ex), nil
+}
+
+// startTx starts the transaction triggered by the begin expectation
+func (c *conn) startTx(ex *ExpectedBegin) *transaction {
+	c.tx = &transaction{conn: c, begin: ex}
+	return c.tx
 }
 
 func (c *sqlmock) begin(opts driver.TxOptions) (*ExpectedBegin, error) {
@@ -230,7 +476,7 @@ func (c *sqlmock) begin(opts driver.TxOptions) (*ExpectedBegin, error) {
 	var fulfilled int
 	for _, next := range c.expected {
 		next.Lock()
-		if next.fulfilled() {
+		if next.exhausted() {
 			next.Unlock()
 			fulfilled++
 			continue
@@ -240,7 +486,12 @@ func (c *sqlmock) begin(opts driver.TxOptions) (*ExpectedBegin, error) {
 			break
 		}
 
+		satisfied := next.fulfilled()
 		next.Unlock()
+		if satisfied {
+			fulfilled++
+			continue
+		}
 		if c.ordered {
 			return nil, fmt.Errorf("call to database transaction Begin, was not expected, next expectation is: %s", next)
 		}
@@ -253,19 +504,18 @@ func (c *sqlmock) begin(opts driver.TxOptions) (*ExpectedBegin, error) {
 		return nil, fmt.Errorf(msg)
 	}
 	defer expected.Unlock()
-	if expected.txOpts != nil &&
-		expected.txOpts.Isolation != opts.Isolation &&
-		expected.txOpts.ReadOnly != opts.ReadOnly {
-		return nil, fmt.Errorf("expected transaction options do not match: %+v, got: %+v", expected.txOpts, opts)
+	if err := expected.matchTxOptions(opts); err != nil {
+		return nil, fmt.Errorf("expected transaction options do not match: %s", err)
 	}
 
-	expected.triggered = true
+	expected.triggered++
+	c.passBefore(expected)
 
 	return expected, expected.err
 }
 
 func (c *sqlmock) ExpectBegin() *ExpectedBegin {
-	e := &ExpectedBegin{}
+	e := &ExpectedBegin{mock: c}
 	c.expected = append(c.expected, e)
 	return e
 }
@@ -279,11 +529,14 @@ func (c *sqlmock) ExpectExec(expectedSQL string) *ExpectedExec {
 }
 
 // Prepare meets http://golang.org/pkg/database/sql/driver/#Conn interface
-func (c *sqlmock) Prepare(query string) (driver.Stmt, error) {
-	ex, err := c.prepare(query)
-	if ex != nil {
-		time.Sleep(ex.delay)
+func (c *conn) Prepare(query string) (_ driver.Stmt, err error) {
+	ex, err := c.prepare(c, query)
+	defer c.record(CallPrepare, query, nil, ex, &err)
+	defer c.invalidate(&err)
+	if ex == nil {
+		return nil, c.reject(err)
 	}
+	time.Sleep(ex.delay)
 	if err != nil {
 		return nil, err
 	}
@@ -291,37 +544,54 @@ func (c *sqlmock) Prepare(query string) (driver.Stmt, error) {
 	return &statement{c, ex, query}, nil
 }
 
-func (c *sqlmock) prepare(query string) (*ExpectedPrepare, error) {
-	var expected *ExpectedPrepare
+func (c *sqlmock) prepare(cn *conn, query string) (*ExpectedPrepare, error) {
+	var expected, scoped *ExpectedPrepare
 	var fulfilled int
-	var ok bool
 
 	for _, next := range c.expected {
 		next.Lock()
-		if next.fulfilled() {
+		if next.exhausted() {
 			next.Unlock()
 			fulfilled++
 			continue
 		}
 
 		if c.ordered {
-			if expected, ok = next.(*ExpectedPrepare); ok {
+			if pr, ok := next.(*ExpectedPrepare); ok && (!pr.fulfilled() || (c.queryMatcher.Match(pr.expectSQL, query) == nil && pr.check(cn) == nil)) {
+				expected = pr
 				break
 			}
 
+			satisfied := next.fulfilled()
 			next.Unlock()
+			if satisfied {
+				fulfilled++
+				continue
+			}
 			return nil, fmt.Errorf("call to Prepare statement with query '%s', was not expected, next expectation is: %s", query, next)
 		}
 
-		if pr, ok := next.(*ExpectedPrepare); ok {
-			if err := c.queryMatcher.Match(pr.expectSQL, query); err == nil {
+		if pr, ok := next.(*ExpectedPrepare); ok && c.queryMatcher.Match(pr.expectSQL, query) == nil {
+			if pr.check(cn) == nil {
 				expected = pr
 				break
 			}
+			if scoped == nil {
+				scoped = pr
+			}
+		}
+		if next.fulfilled() {
+			fulfilled++
 		}
 		next.Unlock()
 	}
 
+	if expected == nil && scoped != nil {
+		// the statement is expected, but in another scope
+		scoped.Lock()
+		expected = scoped
+	}
+
 	if expected == nil {
 		msg := "call to Prepare '%s' query was not expected"
 		if fulfilled == len(c.expected) {
@@ -334,7 +604,12 @@ func (c *sqlmock) prepare(query string) (*ExpectedPrepare, error) {
 		return nil, fmt.Errorf("Prepare: %v", err)
 	}
 
-	expected.triggered = true
+	if err := expected.check(cn); err != nil {
+		return nil, fmt.Errorf("Prepare '%s', %s", query, err)
+	}
+
+	expected.triggered++
+	c.passBefore(expected)
 	return expected, expected.err
 }
 
@@ -365,13 +640,23 @@ func (c *sqlmock) ExpectRollback() *ExpectedRollback {
 }
 
 // Commit meets http://golang.org/pkg/database/sql/driver/#Tx
-func (c *sqlmock) Commit() error {
+func (tx *transaction) Commit() (err error) {
+	ex, err := tx.conn.commit()
+	defer tx.conn.record(CallCommit, "", nil, ex, &err)
+	tx.finish("committed")
+	if ex == nil {
+		return tx.conn.reject(err)
+	}
+	return err
+}
+
+func (c *sqlmock) commit() (*ExpectedCommit, error) {
 	var expected *ExpectedCommit
 	var fulfilled int
 	var ok bool
 	for _, next := range c.expected {
 		next.Lock()
-		if next.fulfilled() {
+		if next.exhausted() {
 			next.Unlock()
 			fulfilled++
 			continue
@@ -381,9 +666,14 @@ func (c *sqlmock) Commit() error {
 			break
 		}
 
+		satisfied := next.fulfilled()
 		next.Unlock()
+		if satisfied {
+			fulfilled++
+			continue
+		}
 		if c.ordered {
-			return fmt.Errorf("call to Commit transaction, was not expected, next expectation is: %s", next)
+			return nil, fmt.Errorf("call to Commit transaction, was not expected, next expectation is: %s", next)
 		}
 	}
 	if expected == nil {
@@ -391,22 +681,33 @@ func (c *sqlmock) Commit() error {
 		if fulfilled == len(c.expected) {
 			msg = "all expectations were already fulfilled, " + msg
 		}
-		return fmt.Errorf(msg)
+		return nil, fmt.Errorf(msg)
 	}
 
-	expected.triggered = true
+	expected.triggered++
+	c.passBefore(expected)
 	expected.Unlock()
-	return expected.err
+	return expected, expected.err
 }
 
 // Rollback meets http://golang.org/pkg/database/sql/driver/#Tx
-func (c *sqlmock) Rollback() error {
+func (tx *transaction) Rollback() (err error) {
+	ex, err := tx.conn.rollback()
+	defer tx.conn.record(CallRollback, "", nil, ex, &err)
+	tx.finish("rolled back")
+	if ex == nil {
+		return tx.conn.reject(err)
+	}
+	return err
+}
+
+func (c *sqlmock) rollback() (*ExpectedRollback, error) {
 	var expected *ExpectedRollback
 	var fulfilled int
 	var ok bool
 	for _, next := range c.expected {
 		next.Lock()
-		if next.fulfilled() {
+		if next.exhausted() {
 			next.Unlock()
 			fulfilled++
 			continue
@@ -416,9 +717,14 @@ func (c *sqlmock) Rollback() error {
 			break
 		}
 
+		satisfied := next.fulfilled()
 		next.Unlock()
+		if satisfied {
+			fulfilled++
+			continue
+		}
 		if c.ordered {
-			return fmt.Errorf("call to Rollback transaction, was not expected, next expectation is: %s", next)
+			return nil, fmt.Errorf("call to Rollback transaction, was not expected, next expectation is: %s", next)
 		}
 	}
 	if expected == nil {
@@ -426,12 +732,13 @@ func (c *sqlmock) Rollback() error {
 		if fulfilled == len(c.expected) {
 			msg = "all expectations were already fulfilled, " + msg
 		}
-		return fmt.Errorf(msg)
+		return nil, fmt.Errorf(msg)
 	}
 
-	expected.triggered = true
+	expected.triggered++
+	c.passBefore(expected)
 	expected.Unlock()
-	return expected.err
+	return expected, expected.err
 }
 
 // NewRows allows Rows to be created from a
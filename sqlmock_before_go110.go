@@ -0,0 +1,11 @@
+//go:build !go1.10
+// +build !go1.10
+
+package sqlmock
+
+import "log"
+
+func (c *sqlmock) ExpectResetSession() *ExpectedResetSession {
+	log.Println("ExpectResetSession has no effect on Go 1.9 or below")
+	return &ExpectedResetSession{}
+}
@@ -21,13 +21,19 @@ type namedValue struct {
 	Value   driver.Value
 }
 
+// Call is a driver call received by the mock,
+// the call history is supported only on Go 1.8 and above
+type Call struct{}
+
+func (c *conn) record(kind CallKind, query string, args []namedValue, ex expectation, err *error) {}
+
 func (c *sqlmock) ExpectPing() *ExpectedPing {
 	log.Println("ExpectPing has no effect on Go 1.7 or below")
 	return &ExpectedPing{}
 }
 
 // Query meets http://golang.org/pkg/database/sql/driver/#Queryer
-func (c *sqlmock) Query(query string, args []driver.Value) (driver.Rows, error) {
+func (c *conn) Query(query string, args []driver.Value) (_ driver.Rows, err error) {
 	namedArgs := make([]namedValue, len(args))
 	for i, v := range args {
 		namedArgs[i] = namedValue{
@@ -36,49 +42,69 @@ func (c *sqlmock) Query(query string, args []driver.Value) (driver.Rows, error)
 		}
 	}
 
-	ex, err := c.query(query, namedArgs)
-	if ex != nil {
-		time.Sleep(ex.delay)
+	ex, err := c.query(c, query, namedArgs)
+	defer c.record(CallQuery, query, namedArgs, ex, &err)
+	defer c.invalidate(&err)
+	if ex == nil {
+		return nil, c.reject(err)
 	}
+	time.Sleep(ex.delay)
 	if err != nil {
 		return nil, err
 	}
 
-	return ex.rows, nil
+	return ex.rowsForCall(), nil
 }
 
-func (c *sqlmock) query(query string, args []namedValue) (*ExpectedQuery, error) {
-	var expected *ExpectedQuery
+func (c *sqlmock) query(cn *conn, query string, args []namedValue) (*ExpectedQuery, error) {
+	if err := c.checkArgCount(query, len(args)); err != nil {
+		return nil, fmt.Errorf("Query '%s', %s", query, err)
+	}
+
+	var expected, scoped *ExpectedQuery
 	var fulfilled int
-	var ok bool
 	for _, next := range c.expected {
 		next.Lock()
-		if next.fulfilled() {
+		if next.exhausted() {
 			next.Unlock()
 			fulfilled++
 			continue
 		}
 
 		if c.ordered {
-			if expected, ok = next.(*ExpectedQuery); ok {
+			if qr, ok := next.(*ExpectedQuery); ok && (!qr.fulfilled() || (qr.matches(c.queryMatcher, query, args) && qr.check(cn) == nil)) {
+				expected = qr
 				break
 			}
+			satisfied := next.fulfilled()
 			next.Unlock()
-			return nil, fmt.Errorf("call to Query '%s' with args %+v, was not expected, next expectation is: %s", query, args, next)
-		}
-		if qr, ok := next.(*ExpectedQuery); ok {
-			if err := c.queryMatcher.Match(qr.expectSQL, query); err != nil {
-				next.Unlock()
+			if satisfied {
+				fulfilled++
 				continue
 			}
-			if err := qr.attemptArgMatch(args); err == nil {
+			return nil, fmt.Errorf("call to Query '%s' with args %+v, was not expected, next expectation is: %s", query, args, next)
+		}
+		if qr, ok := next.(*ExpectedQuery); ok && qr.matches(c.queryMatcher, query, args) {
+			if qr.check(cn) == nil {
 				expected = qr
 				break
 			}
+			if scoped == nil {
+				scoped = qr
+			}
+		}
+		if next.fulfilled() {
+			fulfilled++
 		}
 		next.Unlock()
 	}
 
+	if expected == nil && scoped != nil {
+		// the query is expected, but in another scope
+		scoped.Lock()
+		expected = scoped
+	}
+
 	if expected == nil {
 		msg := "call to Query '%s' with args %+v was not expected"
 		if fulfilled == len(c.expected) {
@@ -97,19 +123,29 @@ func (c *sqlmock) query(query string, args []namedValue) (*ExpectedQuery, error)
 		return nil, fmt.Errorf("Query '%s', arguments do not match: %s", query, err)
 	}
 
-	expected.triggered = true
+	if err := expected.check(cn); err != nil {
+		return nil, fmt.Errorf("Query '%s', %s", query, err)
+	}
+
+	expected.capture(args)
+	expected.triggered++
+	c.passBefore(expected)
 	if expected.err != nil {
 		return expected, expected.err // mocked to return error
 	}
 
 	if expected.rows == nil {
-		return nil, fmt.Errorf("Query '%s' with args %+v, must return a database/sql/driver.Rows, but it was not set for expectation %T as %+v", query, args, expected, expected)
+		return expected, fmt.Errorf("Query '%s' with args %+v, must return a database/sql/driver.Rows, but it was not set for expectation %T as %+v", query, args, expected, expected)
 	}
 	return expected, nil
 }
 
 // Exec meets http://golang.org/pkg/database/sql/driver/#Execer
-func (c *sqlmock) Exec(query string, args []driver.Value) (driver.Result, error) {
+func (c *conn) Exec(query string, args []driver.Value) (_ driver.Result, err error) {
+	if res, ok, err := c.execSavepoint(query); ok {
+		return res, err
+	}
+
 	namedArgs := make([]namedValue, len(args))
 	for i, v := range args {
 		namedArgs[i] = namedValue{
@@ -118,10 +154,13 @@ func (c *sqlmock) Exec(query string, args []driver.Value) (driver.Result, error)
 		}
 	}
 
-	ex, err := c.exec(query, namedArgs)
-	if ex != nil {
-		time.Sleep(ex.delay)
+	ex, err := c.exec(c, query, namedArgs)
+	defer c.record(CallExec, query, namedArgs, ex, &err)
+	defer c.invalidate(&err)
+	if ex == nil {
+		return nil, c.reject(err)
 	}
+	time.Sleep(ex.delay)
 	if err != nil {
 		return nil, err
 	}
@@ -129,38 +168,54 @@ func (c *sqlmock) Exec(query string, args []driver.Value) (driver.Result, error)
 	return ex.result, nil
 }
 
-func (c *sqlmock) exec(query string, args []namedValue) (*ExpectedExec, error) {
-	var expected *ExpectedExec
+func (c *sqlmock) exec(cn *conn, query string, args []namedValue) (*ExpectedExec, error) {
+	if err := c.checkArgCount(query, len(args)); err != nil {
+		return nil, fmt.Errorf("ExecQuery '%s', %s", query, err)
+	}
+
+	var expected, scoped *ExpectedExec
 	var fulfilled int
-	var ok bool
 	for _, next := range c.expected {
 		next.Lock()
-		if next.fulfilled() {
+		if next.exhausted() {
 			next.Unlock()
 			fulfilled++
 			continue
 		}
 
 		if c.ordered {
-			if expected, ok = next.(*ExpectedExec); ok {
+			if exec, ok := next.(*ExpectedExec); ok && (!exec.fulfilled() || (exec.matches(c.queryMatcher, query, args) && exec.check(cn) == nil)) {
+				expected = exec
 				break
 			}
+			satisfied := next.fulfilled()
 			next.Unlock()
-			return nil, fmt.Errorf("call to ExecQuery '%s' with args %+v, was not expected, next expectation is: %s", query, args, next)
-		}
-		if exec, ok := next.(*ExpectedExec); ok {
-			if err := c.queryMatcher.Match(exec.expectSQL, query); err != nil {
-				next.Unlock()
+			if satisfied {
+				fulfilled++
 				continue
 			}
-
-			if err := exec.attemptArgMatch(args); err == nil {
+			return nil, fmt.Errorf("call to ExecQuery '%s' with args %+v, was not expected, next expectation is: %s", query, args, next)
+		}
+		if exec, ok := next.(*ExpectedExec); ok && exec.matches(c.queryMatcher, query, args) {
+			if exec.check(cn) == nil {
 				expected = exec
 				break
 			}
+			if scoped == nil {
+				scoped = exec
+			}
+		}
+		if next.fulfilled() {
+			fulfilled++
 		}
 		next.Unlock()
 	}
+	if expected == nil && scoped != nil {
+		// the query is expected, but in another scope
+		scoped.Lock()
+		expected = scoped
+	}
+
 	if expected == nil {
 		msg := "call to ExecQuery '%s' with args %+v was not expected"
 		if fulfilled == len(c.expected) {
@@ -178,13 +233,19 @@ func (c *sqlmock) exec(query string, args []namedValue) (*ExpectedExec, error) {
 		return nil, fmt.Errorf("ExecQuery '%s', arguments do not match: %s", query, err)
 	}
 
-	expected.triggered = true
+	if err := expected.check(cn); err != nil {
+		return nil, fmt.Errorf("ExecQuery '%s', %s", query, err)
+	}
+
+	expected.capture(args)
+	expected.triggered++
+	c.passBefore(expected)
 	if expected.err != nil {
 		return expected, expected.err // mocked to return error
 	}
 
 	if expected.result == nil {
-		return nil, fmt.Errorf("ExecQuery '%s' with args %+v, must return a database/sql/driver.Result, but it was not set for expectation %T as %+v", query, args, expected, expected)
+		return expected, fmt.Errorf("ExecQuery '%s' with args %+v, must return a database/sql/driver.Result, but it was not set for expectation %T as %+v", query, args, expected, expected)
 	}
 
 	return expected, nil
@@ -0,0 +1,85 @@
+//go:build go1.10
+// +build go1.10
+
+package sqlmock
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"log"
+)
+
+// ResetSession meets https://golang.org/pkg/database/sql/driver/#SessionResetter
+func (c *conn) ResetSession(ctx context.Context) (err error) {
+	if c.bad {
+		return driver.ErrBadConn
+	}
+	if !c.monitorResets {
+		return nil
+	}
+
+	ex, err := c.resetSession()
+	defer c.record(CallResetSession, "", nil, ex, &err)
+	defer c.invalidate(&err)
+	if ex == nil {
+		return c.reject(err)
+	}
+	return err
+}
+
+// IsValid meets https://golang.org/pkg/database/sql/driver/#Validator
+func (c *conn) IsValid() bool {
+	return !c.bad
+}
+
+func (c *sqlmock) resetSession() (*ExpectedResetSession, error) {
+	var expected *ExpectedResetSession
+	var fulfilled int
+	var ok bool
+	for _, next := range c.expected {
+		next.Lock()
+		if next.exhausted() {
+			next.Unlock()
+			fulfilled++
+			continue
+		}
+
+		if expected, ok = next.(*ExpectedResetSession); ok {
+			break
+		}
+
+		satisfied := next.fulfilled()
+		next.Unlock()
+		if satisfied {
+			fulfilled++
+			continue
+		}
+		if c.ordered {
+			return nil, fmt.Errorf("call to connection ResetSession, was not expected, next expectation is: %s", next)
+		}
+	}
+
+	if expected == nil {
+		msg := "call to connection ResetSession was not expected"
+		if fulfilled == len(c.expected) {
+			msg = "all expectations were already fulfilled, " + msg
+		}
+		return nil, fmt.Errorf(msg)
+	}
+
+	expected.triggered++
+	c.passBefore(expected)
+	expected.Unlock()
+	return expected, expected.err
+}
+
+func (c *sqlmock) ExpectResetSession() *ExpectedResetSession {
+	if !c.monitorResets {
+		log.Println("ExpectResetSession will have no effect as monitoring session resets is disabled. Use MonitorSessionResetsOption to enable.")
+		return nil
+	}
+	e := &ExpectedResetSession{}
+	c.expected = append(c.expected, e)
+	return e
+}
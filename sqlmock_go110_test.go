@@ -0,0 +1,102 @@
+//go:build go1.10
+// +build go1.10
+
+package sqlmock
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+)
+
+func TestExecRetriedOnBadConnection(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New(MultipleConnectionsOption(true))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectConnect()
+	mock.ExpectExec("INSERT INTO users").WillReturnBadConn()
+	mock.ExpectClose()
+	mock.ExpectConnect()
+	mock.ExpectExec("INSERT INTO users").WillReturnResult(NewResult(1, 1))
+
+	if _, err := db.Exec("INSERT INTO users(name) VALUES (?)", "john"); err != nil {
+		t.Fatalf("unexpected error on exec: %s", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	var execs []Call
+	for _, call := range mock.Calls() {
+		if call.Kind == CallExec {
+			execs = append(execs, call)
+		}
+	}
+	if len(execs) != 2 {
+		t.Fatalf("expected the insert to be executed twice, but got: %v", execs)
+	}
+	if execs[0].Err != driver.ErrBadConn || execs[0].Conn == execs[1].Conn {
+		t.Errorf("expected the insert to be retried on another connection, but got: %v", execs)
+	}
+}
+
+func TestResetSessionBadConnection(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New(MultipleConnectionsOption(true), MonitorSessionResetsOption(true))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	first := mock.ExpectConnect()
+	mock.ExpectQuery("SELECT name FROM users").WillReturnRows(NewRows([]string{"name"}).AddRow("john"))
+	mock.ExpectResetSession().WillReturnBadConn()
+	mock.ExpectClose()
+	second := mock.ExpectConnect()
+	mock.ExpectQuery("SELECT name FROM users").WillReturnRows(NewRows([]string{"name"}).AddRow("jane"))
+
+	var name string
+	for i := 0; i < 2; i++ {
+		if err := db.QueryRow("SELECT name FROM users").Scan(&name); err != nil {
+			t.Fatalf("unexpected error on query %d: %s", i, err)
+		}
+	}
+	if name != "jane" {
+		t.Errorf("expected the second query to run on a new connection, but got %q", name)
+	}
+	if first.ID() == second.ID() {
+		t.Errorf("expected distinct connections, but got %d twice", first.ID())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestConnectionInvalidatedByBadConnection(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin().WillReturnBadConn()
+
+	cn := &conn{sqlmock: mock.(*sqlmock)}
+	if !cn.IsValid() {
+		t.Fatal("expected a new connection to be valid")
+	}
+	if _, err := cn.BeginTx(context.Background(), driver.TxOptions{}); err != driver.ErrBadConn {
+		t.Fatalf("expected a bad connection error, but got: %v", err)
+	}
+	if cn.IsValid() {
+		t.Error("expected the connection to be invalidated")
+	}
+	if err := cn.ResetSession(context.Background()); err != driver.ErrBadConn {
+		t.Errorf("expected session reset to fail on a bad connection, but got: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+//go:build go1.14
+// +build go1.14
+
+package sqlmock
+
+import (
+	"database/sql"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// NewT creates sqlmock database connection and a mock to manage expectations
+// bound to the given test. Accepts the same options as New.
+//
+// The test fails immediately if the mock cannot be created. Once the test and
+// its subtests complete, the expectations are verified and any problem is
+// reported using t.Errorf, then the database is closed.
+// Use FailOnUnexpectedCallOption to report unexpected calls right away.
+func NewT(t testing.TB, options ...SqlMockOption) (*sql.DB, Sqlmock) {
+	t.Helper()
+	db, mock, err := New(options...)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	smock := mock.(*sqlmock)
+	smock.mu.Lock()
+	smock.onUnexpected = func(err error) {
+		t.Errorf("unexpected call was rejected: %s%s", err, callerStack())
+	}
+	smock.mu.Unlock()
+
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+
+		// closing the database is not a part of the test
+		smock.mu.Lock()
+		smock.onUnexpected = nil
+		smock.mu.Unlock()
+		db.Close()
+	})
+	return db, mock
+}
+
+// callerStack returns the stack of the code, which called
+// the database, leaving out database/sql and sqlmock frames
+func callerStack() string {
+	pc := make([]uintptr, 64)
+	n := runtime.Callers(2, pc)
+	frames := runtime.CallersFrames(pc[:n])
+
+	var lines []string
+	for {
+		frame, more := frames.Next()
+		switch {
+		case strings.HasPrefix(frame.Function, "database/sql."):
+			// everything seen so far was called by database/sql
+			lines = nil
+		case strings.HasPrefix(frame.Function, "testing."), strings.HasPrefix(frame.Function, "runtime."):
+			more = false
+		default:
+			lines = append(lines, fmt.Sprintf("\n\t%s\n\t\t%s:%d", frame.Function, frame.File, frame.Line))
+		}
+		if !more {
+			break
+		}
+	}
+	return strings.Join(lines, "")
+}
@@ -0,0 +1,87 @@
+//go:build go1.14
+// +build go1.14
+
+package sqlmock
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// recordingT captures the failures and cleanups registered
+// by NewT, instead of failing the running test
+type recordingT struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingT) Cleanup(f func()) {
+	r.cleanups = append(r.cleanups, f)
+}
+
+func (r *recordingT) cleanup() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+func TestNewTVerifiesExpectationsOnCleanup(t *testing.T) {
+	rt := &recordingT{TB: t}
+	db, mock := NewT(rt)
+
+	mock.ExpectExec("UPDATE users").WillReturnResult(NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if _, err := db.Exec("UPDATE users SET active = 1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(rt.cleanups) != 1 {
+		t.Fatalf("expected a cleanup to be registered, but got %d", len(rt.cleanups))
+	}
+
+	rt.cleanup()
+
+	if len(rt.errors) != 1 || !strings.Contains(rt.errors[0], "ExpectedCommit") {
+		t.Errorf("expected unmet commit to be reported, but got: %v", rt.errors)
+	}
+	if err := db.Ping(); err == nil {
+		t.Error("expected database to be closed")
+	}
+}
+
+func TestNewTFailOnUnexpectedCall(t *testing.T) {
+	rt := &recordingT{TB: t}
+	db, mock := NewT(rt, FailOnUnexpectedCallOption(true))
+
+	if _, err := db.Exec("DELETE FROM users"); err == nil {
+		t.Fatal("expected an error, since exec was not expected")
+	}
+	if len(rt.errors) != 1 {
+		t.Fatalf("expected unexpected call to be reported right away, but got: %v", rt.errors)
+	}
+	if !strings.Contains(rt.errors[0], "call to ExecQuery 'DELETE FROM users'") {
+		t.Errorf("expected report to name the rejected call, but got: %s", rt.errors[0])
+	}
+	if !strings.Contains(rt.errors[0], "TestNewTFailOnUnexpectedCall") {
+		t.Errorf("expected report to include the caller stack, but got: %s", rt.errors[0])
+	}
+	if strings.Contains(rt.errors[0], "database/sql.") {
+		t.Errorf("expected database/sql frames to be left out, but got: %s", rt.errors[0])
+	}
+
+	rt.cleanup()
+	if len(rt.errors) != 1 {
+		t.Errorf("expected no more failures on cleanup, but got: %v", rt.errors[1:])
+	}
+	if len(mock.Calls()) != 2 || len(mock.UnexpectedCalls()) != 1 {
+		t.Errorf("expected the exec and close calls in history, with the exec rejected, but got: %v", mock.Calls())
+	}
+}
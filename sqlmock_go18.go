@@ -8,6 +8,7 @@ import (
 	"database/sql/driver"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"time"
 )
@@ -23,6 +24,28 @@ type Sqlmock interface {
 
 	// New Column allows to create a Column
 	NewColumn(name string) *Column
+
+	// Calls returns the history of every Query, Exec, Prepare, Begin,
+	// Commit, Rollback, Ping and Close call received by the mock,
+	// including the calls which were not expected. Ping calls are
+	// recorded only if pings are monitored, see MonitorPingsOption.
+	Calls() []Call
+
+	// UnexpectedCalls returns the calls which did not match any
+	// expectation and were rejected. Unless disabled with
+	// ReportUnexpectedCallsOption, these calls are also reported
+	// by ExpectationsWereMet, even if the code under test ignored
+	// the returned errors. Closes of idle connections are not
+	// rejected, unless any close was expected.
+	UnexpectedCalls() []Call
+
+	// DumpAsGo writes the calls received by the mock, including
+	// the unexpected ones, as Go code expecting them in order.
+	DumpAsGo(w io.Writer) error
+
+	// MatchGoldenGo compares the calls dumped by DumpAsGo with the
+	// golden file at path, or writes it if SQLMOCK_UPDATE is set.
+	MatchGoldenGo(path string) error
 }
 
 // ErrCancelled defines an error value, which can be expected in case of
@@ -30,62 +53,80 @@ type Sqlmock interface {
 var ErrCancelled = errors.New("canceling query due to user request")
 
 // Implement the "QueryerContext" interface
-func (c *sqlmock) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
-	ex, err := c.query(query, args)
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (_ driver.Rows, err error) {
+	ex, err := c.query(c, query, args)
+	defer c.record(CallQuery, query, args, ex, &err)
+	defer c.invalidate(&err)
 	if ex != nil {
 		select {
 		case <-time.After(ex.delay):
 			if err != nil {
 				return nil, err
 			}
-			return ex.rows, nil
+			if ex.responder != nil {
+				return ex.respond(ctx, query, args)
+			}
+			return ex.rowsForCall(), nil
 		case <-ctx.Done():
 			return nil, ErrCancelled
 		}
 	}
 
-	return nil, err
+	return nil, c.reject(err)
 }
 
 // Implement the "ExecerContext" interface
-func (c *sqlmock) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
-	ex, err := c.exec(query, args)
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (_ driver.Result, err error) {
+	if res, ok, err := c.execSavepoint(query); ok {
+		return res, err
+	}
+
+	ex, err := c.exec(c, query, args)
+	defer c.record(CallExec, query, args, ex, &err)
+	defer c.invalidate(&err)
 	if ex != nil {
 		select {
 		case <-time.After(ex.delay):
 			if err != nil {
 				return nil, err
 			}
+			if ex.responder != nil {
+				return ex.respond(ctx, query, args)
+			}
 			return ex.result, nil
 		case <-ctx.Done():
 			return nil, ErrCancelled
 		}
 	}
 
-	return nil, err
+	return nil, c.reject(err)
 }
 
 // Implement the "ConnBeginTx" interface
-func (c *sqlmock) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (_ driver.Tx, err error) {
 	ex, err := c.begin(opts)
+	defer c.record(CallBegin, "", nil, ex, &err)
+	defer c.invalidate(&err)
 	if ex != nil {
 		select {
 		case <-time.After(ex.delay):
 			if err != nil {
 				return nil, err
 			}
-			return c, nil
+			return c.startTx(ex), nil
 		case <-ctx.Done():
 			return nil, ErrCancelled
 		}
 	}
 
-	return nil, err
+	return nil, c.reject(err)
 }
 
 // Implement the "ConnPrepareContext" interface
-func (c *sqlmock) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
-	ex, err := c.prepare(query)
+func (c *conn) PrepareContext(ctx context.Context, query string) (_ driver.Stmt, err error) {
+	ex, err := c.prepare(c, query)
+	defer c.record(CallPrepare, query, nil, ex, &err)
+	defer c.invalidate(&err)
 	if ex != nil {
 		select {
 		case <-time.After(ex.delay):
@@ -98,22 +139,25 @@ func (c *sqlmock) PrepareContext(ctx context.Context, query string) (driver.Stmt
 		}
 	}
 
-	return nil, err
+	return nil, c.reject(err)
 }
 
 // Implement the "Pinger" interface - the explicit DB driver ping was only added to database/sql in Go 1.8
-func (c *sqlmock) Ping(ctx context.Context) error {
+func (c *conn) Ping(ctx context.Context) (err error) {
 	if !c.monitorPings {
 		return nil
 	}
 
 	ex, err := c.ping()
-	if ex != nil {
-		select {
-		case <-ctx.Done():
-			return ErrCancelled
-		case <-time.After(ex.delay):
-		}
+	defer c.record(CallPing, "", nil, ex, &err)
+	if ex == nil {
+		return c.reject(err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return ErrCancelled
+	case <-time.After(ex.delay):
 	}
 
 	return err
@@ -125,7 +169,7 @@ func (c *sqlmock) ping() (*ExpectedPing, error) {
 	var ok bool
 	for _, next := range c.expected {
 		next.Lock()
-		if next.fulfilled() {
+		if next.exhausted() {
 			next.Unlock()
 			fulfilled++
 			continue
@@ -135,7 +179,12 @@ func (c *sqlmock) ping() (*ExpectedPing, error) {
 			break
 		}
 
+		satisfied := next.fulfilled()
 		next.Unlock()
+		if satisfied {
+			fulfilled++
+			continue
+		}
 		if c.ordered {
 			return nil, fmt.Errorf("call to database Ping, was not expected, next expectation is: %s", next)
 		}
@@ -149,7 +198,8 @@ func (c *sqlmock) ping() (*ExpectedPing, error) {
 		return nil, fmt.Errorf(msg)
 	}
 
-	expected.triggered = true
+	expected.triggered++
+	c.passBefore(expected)
 	expected.Unlock()
 	return expected, expected.err
 }
@@ -176,7 +226,7 @@ func (c *sqlmock) ExpectPing() *ExpectedPing {
 
 // Query meets http://golang.org/pkg/database/sql/driver/#Queryer
 // Deprecated: Drivers should implement QueryerContext instead.
-func (c *sqlmock) Query(query string, args []driver.Value) (driver.Rows, error) {
+func (c *conn) Query(query string, args []driver.Value) (_ driver.Rows, err error) {
 	namedArgs := make([]driver.NamedValue, len(args))
 	for i, v := range args {
 		namedArgs[i] = driver.NamedValue{
@@ -185,49 +235,72 @@ func (c *sqlmock) Query(query string, args []driver.Value) (driver.Rows, error)
 		}
 	}
 
-	ex, err := c.query(query, namedArgs)
-	if ex != nil {
-		time.Sleep(ex.delay)
+	ex, err := c.query(c, query, namedArgs)
+	defer c.record(CallQuery, query, namedArgs, ex, &err)
+	defer c.invalidate(&err)
+	if ex == nil {
+		return nil, c.reject(err)
 	}
+	time.Sleep(ex.delay)
 	if err != nil {
 		return nil, err
 	}
 
-	return ex.rows, nil
+	if ex.responder != nil {
+		return ex.respond(context.Background(), query, namedArgs)
+	}
+	return ex.rowsForCall(), nil
 }
 
-func (c *sqlmock) query(query string, args []driver.NamedValue) (*ExpectedQuery, error) {
-	var expected *ExpectedQuery
+func (c *sqlmock) query(cn *conn, query string, args []driver.NamedValue) (*ExpectedQuery, error) {
+	if err := c.checkArgCount(query, len(args)); err != nil {
+		return nil, fmt.Errorf("Query '%s', %s", query, err)
+	}
+
+	var expected, scoped *ExpectedQuery
 	var fulfilled int
-	var ok bool
 	for _, next := range c.expected {
 		next.Lock()
-		if next.fulfilled() {
+		if next.exhausted() {
 			next.Unlock()
 			fulfilled++
 			continue
 		}
 
 		if c.ordered {
-			if expected, ok = next.(*ExpectedQuery); ok {
+			if qr, ok := next.(*ExpectedQuery); ok && (!qr.fulfilled() || (qr.matches(c.queryMatcher, query, args) && qr.check(cn) == nil)) {
+				expected = qr
 				break
 			}
+			satisfied := next.fulfilled()
 			next.Unlock()
-			return nil, fmt.Errorf("call to Query '%s' with args %+v, was not expected, next expectation is: %s", query, args, next)
-		}
-		if qr, ok := next.(*ExpectedQuery); ok {
-			if err := c.queryMatcher.Match(qr.expectSQL, query); err != nil {
-				next.Unlock()
+			if satisfied {
+				fulfilled++
 				continue
 			}
-			if err := qr.attemptArgMatch(args); err == nil {
+			return nil, fmt.Errorf("call to Query '%s' with args %+v, was not expected, next expectation is: %s", query, args, next)
+		}
+		if qr, ok := next.(*ExpectedQuery); ok && qr.matches(c.queryMatcher, query, args) {
+			if qr.check(cn) == nil {
 				expected = qr
 				break
 			}
+			if scoped == nil {
+				scoped = qr
+			}
+		}
+		if next.fulfilled() {
+			fulfilled++
 		}
 		next.Unlock()
 	}
 
+	if expected == nil && scoped != nil {
+		// the query is expected, but in another scope
+		scoped.Lock()
+		expected = scoped
+	}
+
 	if expected == nil {
 		msg := "call to Query '%s' with args %+v was not expected"
 		if fulfilled == len(c.expected) {
@@ -246,20 +319,30 @@ func (c *sqlmock) query(query string, args []driver.NamedValue) (*ExpectedQuery,
 		return nil, fmt.Errorf("Query '%s', arguments do not match: %s", query, err)
 	}
 
-	expected.triggered = true
+	if err := expected.check(cn); err != nil {
+		return nil, fmt.Errorf("Query '%s', %s", query, err)
+	}
+
+	expected.capture(args)
+	expected.triggered++
+	c.passBefore(expected)
 	if expected.err != nil {
 		return expected, expected.err // mocked to return error
 	}
 
-	if expected.rows == nil {
-		return nil, fmt.Errorf("Query '%s' with args %+v, must return a database/sql/driver.Rows, but it was not set for expectation %T as %+v", query, args, expected, expected)
+	if expected.rows == nil && expected.responder == nil {
+		return expected, fmt.Errorf("Query '%s' with args %+v, must return a database/sql/driver.Rows, but it was not set for expectation %T as %+v", query, args, expected, expected)
 	}
 	return expected, nil
 }
 
 // Exec meets http://golang.org/pkg/database/sql/driver/#Execer
 // Deprecated: Drivers should implement ExecerContext instead.
-func (c *sqlmock) Exec(query string, args []driver.Value) (driver.Result, error) {
+func (c *conn) Exec(query string, args []driver.Value) (_ driver.Result, err error) {
+	if res, ok, err := c.execSavepoint(query); ok {
+		return res, err
+	}
+
 	namedArgs := make([]driver.NamedValue, len(args))
 	for i, v := range args {
 		namedArgs[i] = driver.NamedValue{
@@ -268,49 +351,71 @@ func (c *sqlmock) Exec(query string, args []driver.Value) (driver.Result, error)
 		}
 	}
 
-	ex, err := c.exec(query, namedArgs)
-	if ex != nil {
-		time.Sleep(ex.delay)
+	ex, err := c.exec(c, query, namedArgs)
+	defer c.record(CallExec, query, namedArgs, ex, &err)
+	defer c.invalidate(&err)
+	if ex == nil {
+		return nil, c.reject(err)
 	}
+	time.Sleep(ex.delay)
 	if err != nil {
 		return nil, err
 	}
 
+	if ex.responder != nil {
+		return ex.respond(context.Background(), query, namedArgs)
+	}
 	return ex.result, nil
 }
 
-func (c *sqlmock) exec(query string, args []driver.NamedValue) (*ExpectedExec, error) {
-	var expected *ExpectedExec
+func (c *sqlmock) exec(cn *conn, query string, args []driver.NamedValue) (*ExpectedExec, error) {
+	if err := c.checkArgCount(query, len(args)); err != nil {
+		return nil, fmt.Errorf("ExecQuery '%s', %s", query, err)
+	}
+
+	var expected, scoped *ExpectedExec
 	var fulfilled int
-	var ok bool
 	for _, next := range c.expected {
 		next.Lock()
-		if next.fulfilled() {
+		if next.exhausted() {
 			next.Unlock()
 			fulfilled++
 			continue
 		}
 
 		if c.ordered {
-			if expected, ok = next.(*ExpectedExec); ok {
+			if exec, ok := next.(*ExpectedExec); ok && (!exec.fulfilled() || (exec.matches(c.queryMatcher, query, args) && exec.check(cn) == nil)) {
+				expected = exec
 				break
 			}
+			satisfied := next.fulfilled()
 			next.Unlock()
-			return nil, fmt.Errorf("call to ExecQuery '%s' with args %+v, was not expected, next expectation is: %s", query, args, next)
-		}
-		if exec, ok := next.(*ExpectedExec); ok {
-			if err := c.queryMatcher.Match(exec.expectSQL, query); err != nil {
-				next.Unlock()
+			if satisfied {
+				fulfilled++
 				continue
 			}
-
-			if err := exec.attemptArgMatch(args); err == nil {
+			return nil, fmt.Errorf("call to ExecQuery '%s' with args %+v, was not expected, next expectation is: %s", query, args, next)
+		}
+		if exec, ok := next.(*ExpectedExec); ok && exec.matches(c.queryMatcher, query, args) {
+			if exec.check(cn) == nil {
 				expected = exec
 				break
 			}
+			if scoped == nil {
+				scoped = exec
+			}
+		}
+		if next.fulfilled() {
+			fulfilled++
 		}
 		next.Unlock()
 	}
+	if expected == nil && scoped != nil {
+		// the query is expected, but in another scope
+		scoped.Lock()
+		expected = scoped
+	}
+
 	if expected == nil {
 		msg := "call to ExecQuery '%s' with args %+v was not expected"
 		if fulfilled == len(c.expected) {
@@ -328,13 +433,19 @@ func (c *sqlmock) exec(query string, args []driver.NamedValue) (*ExpectedExec, e
 		return nil, fmt.Errorf("ExecQuery '%s', arguments do not match: %s", query, err)
 	}
 
-	expected.triggered = true
+	if err := expected.check(cn); err != nil {
+		return nil, fmt.Errorf("ExecQuery '%s', %s", query, err)
+	}
+
+	expected.capture(args)
+	expected.triggered++
+	c.passBefore(expected)
 	if expected.err != nil {
 		return expected, expected.err // mocked to return error
 	}
 
-	if expected.result == nil {
-		return nil, fmt.Errorf("ExecQuery '%s' with args %+v, must return a database/sql/driver.Result, but it was not set for expectation %T as %+v", query, args, expected, expected)
+	if expected.result == nil && expected.responder == nil {
+		return expected, fmt.Errorf("ExecQuery '%s' with args %+v, must return a database/sql/driver.Result, but it was not set for expectation %T as %+v", query, args, expected, expected)
 	}
 
 	return expected, nil
@@ -6,7 +6,10 @@ package sqlmock
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 )
@@ -380,7 +383,7 @@ func TestContextBeginWithTxOptions(t *testing.T) {
 		cancel()
 	}()
 
-	_, err = db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted, ReadOnly: false})
+	_, err = db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted, ReadOnly: true})
 	if err != nil {
 		t.Errorf("error was not expected, but got: %v", err)
 	}
@@ -420,6 +423,52 @@ func TestContextBeginWithTxOptionsMismatch(t *testing.T) {
 	}
 }
 
+func TestBeginWithPartialTxOptions(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin().WithIsolation(sql.LevelSerializable)
+	mock.ExpectBegin().WithReadOnly(true)
+
+	ctx := context.Background()
+	_, err = db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err == nil || !strings.Contains(err.Error(), "expected isolation level Serializable, but got Read Committed") {
+		t.Fatalf("expected isolation level mismatch, but got: %v", err)
+	}
+	if _, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}); err != nil {
+		t.Fatalf("error was not expected, but got: %v", err)
+	}
+	_, err = db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err == nil || !strings.Contains(err.Error(), "expected read only to be true, but got false") {
+		t.Fatalf("expected read only mismatch, but got: %v", err)
+	}
+}
+
+func TestBeginWithTxOptionsMatcher(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin().WithTxOptionsMatcher(func(opts driver.TxOptions) error {
+		if sql.IsolationLevel(opts.Isolation) < sql.LevelRepeatableRead {
+			return fmt.Errorf("isolation level %s is too weak", sql.IsolationLevel(opts.Isolation))
+		}
+		return nil
+	})
+
+	_, err = db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err == nil || !strings.Contains(err.Error(), "isolation level Read Committed is too weak") {
+		t.Fatalf("expected tx options matcher error, but got: %v", err)
+	}
+}
+
 func TestContextPrepareCancel(t *testing.T) {
 	t.Parallel()
 	db, mock, err := New()
@@ -699,3 +748,148 @@ func TestPingExpectationsContextTimeout(t *testing.T) {
 		t.Errorf("expected Ping to return after context timeout, but it did not in a timely fashion")
 	}
 }
+
+func TestQueryWillRespond(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT name FROM users WHERE id = ?").
+		WillRespond(func(ctx context.Context, query string, args []driver.NamedValue) (*Rows, error) {
+			if args[0].Value.(int64) > 2 {
+				return nil, errors.New("user not found")
+			}
+			return NewRows([]string{"name"}).AddRow(fmt.Sprintf("user%d", args[0].Value)), nil
+		}).
+		Times(3)
+
+	for id := 1; id <= 2; id++ {
+		var name string
+		if err := db.QueryRow("SELECT name FROM users WHERE id = ?", id).Scan(&name); err != nil {
+			t.Fatalf("unexpected error for user %d: %s", id, err)
+		}
+		if expected := fmt.Sprintf("user%d", id); name != expected {
+			t.Errorf("expected name %q, but got %q", expected, name)
+		}
+	}
+
+	var name string
+	if err := db.QueryRow("SELECT name FROM users WHERE id = ?", 3).Scan(&name); err == nil || err.Error() != "user not found" {
+		t.Errorf("expected responder error, but got: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestExecWillRespond(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	var lastID int64
+	mock.ExpectExec("INSERT INTO users").
+		WillRespond(func(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+			lastID++
+			return NewResult(lastID, 1), nil
+		}).
+		AnyTimes()
+
+	for i := int64(1); i <= 3; i++ {
+		res, err := db.Exec("INSERT INTO users(name) VALUES (?)", "john")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if id, _ := res.LastInsertId(); id != i {
+			t.Errorf("expected last insert id %d, but got %d", i, id)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestWillRespondContextCancel(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	var called bool
+	mock.ExpectExec("DELETE FROM users").
+		WillDelayFor(time.Second).
+		WillRespond(func(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+			called = true
+			return NewResult(0, 1), nil
+		})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := db.ExecContext(ctx, "DELETE FROM users"); err != ErrCancelled {
+		t.Errorf("was expecting cancel error, but got: %v", err)
+	}
+	if called {
+		t.Error("responder should not be called when the context is cancelled")
+	}
+}
+
+func TestWillRespondWithoutRows(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").
+		WillRespond(func(ctx context.Context, query string, args []driver.NamedValue) (*Rows, error) {
+			return nil, nil
+		})
+
+	if _, err := db.Query("SELECT"); err == nil {
+		t.Error("expected an error, since responder returned neither rows nor error")
+	}
+}
+
+func TestExecWithNamedArgsInAnyOrder(t *testing.T) {
+	t.Parallel()
+	db, mock, err := New()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE users").
+		WithNamedArgs(map[string]driver.Value{"id": 5, "name": AnyArg()}).
+		WillReturnResult(NewResult(0, 1))
+	mock.ExpectQuery("SELECT name FROM users").
+		WithArgs(sql.Named("limit", 10), sql.Named("offset", 20)).
+		WillReturnRows(NewRows([]string{"name"}))
+
+	_, err = db.Exec("UPDATE users SET name = :name WHERE id = :id", sql.Named("name", "john"), sql.Named("id", 5))
+	if err != nil {
+		t.Errorf("error '%s' was not expected, while updating a row", err)
+	}
+
+	rows, err := db.Query("SELECT name FROM users OFFSET :offset LIMIT :limit", sql.Named("offset", 20), sql.Named("limit", 10))
+	if err != nil {
+		t.Errorf("error '%s' was not expected, while querying rows", err)
+	} else {
+		rows.Close()
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
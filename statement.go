@@ -1,7 +1,7 @@
 package sqlmock
 
 type statement struct {
-	conn  *sqlmock
+	conn  *conn
 	ex    *ExpectedPrepare
 	query string
 }
@@ -11,6 +11,8 @@ func (stmt *statement) Close() error {
 	return stmt.ex.closeErr
 }
 
+// NumInput returns the number of the query placeholders,
+// or -1 if the placeholders are not parsed
 func (stmt *statement) NumInput() int {
-	return -1
+	return countPlaceholders(stmt.query, stmt.conn.placeholders)
 }
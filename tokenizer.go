@@ -0,0 +1,201 @@
+package sqlmock
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind is the kind of a lexical SQL token
+type tokenKind int
+
+const (
+	tokenWord        tokenKind = iota // keyword or identifier, maybe quoted
+	tokenString                       // string literal
+	tokenNumber                       // numeric literal
+	tokenPlaceholder                  // ?, $1, :name, :1 or @name
+	tokenSymbol                       // operator or punctuation
+)
+
+// sqlToken is a lexical SQL token
+type sqlToken struct {
+	kind tokenKind
+	text string // the token as written, quotes included
+	pos  int    // byte offset of the token in the query
+}
+
+// normalized returns the token in the form used to compare
+// queries: words are unquoted and upper cased, placeholders
+// of every style become ?
+func (t sqlToken) normalized() string {
+	switch t.kind {
+	case tokenWord:
+		return strings.ToUpper(unquoteIdent(t.text))
+	case tokenPlaceholder:
+		return "?"
+	}
+	return t.text
+}
+
+// multi character operators, longest first
+var sqlOperators = []string{"->>", "<=>", "<>", "<=", ">=", "!=", "||", "::", "->", "#>", "<<", ">>", "&&"}
+
+// tokenizeSQL splits the query into lexical tokens, leaving out
+// whitespace and comments. It does not validate the syntax, but
+// fails on unterminated quotes and comments.
+func tokenizeSQL(query string) ([]sqlToken, error) {
+	var tokens []sqlToken
+	for i := 0; i < len(query); {
+		c := query[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+			continue
+		case strings.HasPrefix(query[i:], "--"):
+			if end := strings.IndexByte(query[i:], '\n'); end >= 0 {
+				i += end + 1
+			} else {
+				i = len(query)
+			}
+			continue
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment at position %d", start)
+			}
+			i += end + 4
+			continue
+		case c == '\'':
+			end, err := closingQuote(query, i, '\'')
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, sqlToken{kind: tokenString, text: query[start:i], pos: start})
+			continue
+		case c == '"' || c == '`':
+			end, err := closingQuote(query, i, c)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+		case c == '[' && i+1 < len(query) && isIdentStart(query[i+1]):
+			// [quoted identifier] of sql server, otherwise a subscript as arr[i + 1]
+			end := scan(query, i+1, func(b byte) bool { return isIdentChar(b) || b == ' ' })
+			if end >= len(query) {
+				return nil, fmt.Errorf("unterminated quoted identifier at position %d", start)
+			}
+			if query[end] != ']' {
+				i++
+				tokens = append(tokens, sqlToken{kind: tokenSymbol, text: "[", pos: start})
+				continue
+			}
+			i = end + 1
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			i = scan(query, i+1, isDigit)
+			tokens = append(tokens, sqlToken{kind: tokenPlaceholder, text: query[start:i], pos: start})
+			continue
+		case c == '$':
+			// dollar quoted string: $$...$$ or $tag$...$tag$
+			tagEnd := scan(query, i+1, func(b byte) bool { return isIdentChar(b) && b != '$' })
+			if tagEnd >= len(query) || query[tagEnd] != '$' {
+				i++
+				tokens = append(tokens, sqlToken{kind: tokenSymbol, text: "$", pos: start})
+				continue
+			}
+			tag := query[i : tagEnd+1]
+			end := strings.Index(query[tagEnd+1:], tag)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated dollar quoted string at position %d", start)
+			}
+			i = tagEnd + 1 + end + len(tag)
+			tokens = append(tokens, sqlToken{kind: tokenString, text: query[start:i], pos: start})
+			continue
+		case c == '?':
+			i = scan(query, i+1, isDigit) // ?1 of sqlite
+			tokens = append(tokens, sqlToken{kind: tokenPlaceholder, text: query[start:i], pos: start})
+			continue
+		case c == '@' && strings.HasPrefix(query[i:], "@@"):
+			// system variable, as @@ROWCOUNT
+			i = scan(query, i+2, isIdentChar)
+		case (c == ':' || c == '@') && i+1 < len(query) && isIdentStart(query[i+1]) &&
+			!(c == ':' && i > 0 && query[i-1] == ':'):
+			i = scan(query, i+1, isIdentChar)
+			tokens = append(tokens, sqlToken{kind: tokenPlaceholder, text: query[start:i], pos: start})
+			continue
+		case c == ':' && i+1 < len(query) && isDigit(query[i+1]) &&
+			(i == 0 || !isIdentChar(query[i-1]) && query[i-1] != ':' && query[i-1] != ']'):
+			// positional :1 of oracle, but not a slice as arr[1:2]
+			i = scan(query, i+1, isDigit)
+			tokens = append(tokens, sqlToken{kind: tokenPlaceholder, text: query[start:i], pos: start})
+			continue
+		case isDigit(c) || c == '.' && i+1 < len(query) && isDigit(query[i+1]):
+			i = scan(query, i, func(b byte) bool { return isDigit(b) || b == '.' })
+			if i < len(query) && (query[i] == 'e' || query[i] == 'E') {
+				exp := i + 1
+				if exp < len(query) && (query[exp] == '+' || query[exp] == '-') {
+					exp++
+				}
+				if exp < len(query) && isDigit(query[exp]) {
+					i = scan(query, exp, isDigit)
+				}
+			}
+			tokens = append(tokens, sqlToken{kind: tokenNumber, text: query[start:i], pos: start})
+			continue
+		case isIdentStart(c):
+			i = scan(query, i, isIdentChar)
+		default:
+			i++
+			for _, op := range sqlOperators {
+				if strings.HasPrefix(query[start:], op) {
+					i = start + len(op)
+					break
+				}
+			}
+			tokens = append(tokens, sqlToken{kind: tokenSymbol, text: query[start:i], pos: start})
+			continue
+		}
+		tokens = append(tokens, sqlToken{kind: tokenWord, text: query[start:i], pos: start})
+	}
+	return tokens, nil
+}
+
+// closingQuote returns the offset after the quote closing the one at
+// start, a doubled quote is an escaped one
+func closingQuote(query string, start int, quote byte) (int, error) {
+	for i := start + 1; i < len(query); i++ {
+		if query[i] != quote {
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1, nil
+	}
+	if quote == '\'' {
+		return 0, fmt.Errorf("unterminated string at position %d", start)
+	}
+	return 0, fmt.Errorf("unterminated quoted identifier at position %d", start)
+}
+
+// scan returns the offset of the first byte from i, which is not accepted
+func scan(query string, i int, accept func(byte) bool) int {
+	for i < len(query) && accept(query[i]) {
+		i++
+	}
+	return i
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func isIdentStart(b byte) bool {
+	return b == '_' || b >= 0x80 || unicode.IsLetter(rune(b))
+}
+
+func isIdentChar(b byte) bool {
+	return isIdentStart(b) || isDigit(b) || b == '$'
+}
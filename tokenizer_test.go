@@ -0,0 +1,47 @@
+package sqlmock
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenizeSQL(t *testing.T) {
+	cases := []struct {
+		query    string
+		expected []string
+	}{
+		{"SELECT * FROM t", []string{"SELECT", "*", "FROM", "t"}},
+		{"a<=b AND c->>'k' <> $1", []string{"a", "<=", "b", "AND", "c", "->>", "'k'", "<>", "$1"}},
+		{"x::int = :x", []string{"x", "::", "int", "=", ":x"}},
+		{"a = :1 AND b=:2", []string{"a", "=", ":1", "AND", "b", "=", ":2"}},
+		{"WHERE n = @name AND m = ?2", []string{"WHERE", "n", "=", "@name", "AND", "m", "=", "?2"}},
+		{"SELECT @@ROWCOUNT", []string{"SELECT", "@@ROWCOUNT"}},
+		{"SELECT $$it's$$, $tag$a$b$tag$", []string{"SELECT", "$$it's$$", ",", "$tag$a$b$tag$"}},
+		{"SELECT \"a\"\"b\", [c d], `e`", []string{"SELECT", `"a""b"`, ",", "[c d]", ",", "`e`"}},
+		{"SELECT arr[ 1 ], arr[i+1], data['k'] FROM t", []string{"SELECT", "arr", "[", "1", "]", ",", "arr", "[", "i", "+", "1", "]", ",", "data", "[", "'k'", "]", "FROM", "t"}},
+		{"SELECT 1 -- one\n, .5, 2e-3 /* two\nlines */", []string{"SELECT", "1", ",", ".5", ",", "2e-3"}},
+	}
+
+	for i, c := range cases {
+		tokens, err := tokenizeSQL(c.query)
+		if err != nil {
+			t.Errorf("unexpected error at %d case: %s", i, err)
+			continue
+		}
+		var actual []string
+		for _, tok := range tokens {
+			actual = append(actual, tok.text)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("expected tokens %q, but got %q at %d case", c.expected, actual, i)
+		}
+	}
+}
+
+func TestTokenizeSQLUnterminated(t *testing.T) {
+	for _, query := range []string{"SELECT 'a", `SELECT "a`, "SELECT [a", "SELECT $$a", "SELECT /* a"} {
+		if _, err := tokenizeSQL(query); err == nil {
+			t.Errorf("expected an error for %q", query)
+		}
+	}
+}